@@ -1,105 +1,78 @@
 package kryptono
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 )
 
 type NewOrderRequest struct {
-	OrderSymbol string  `json:"order_symbol"`
-	OrderSide   string  `json:"order_side"`
-	OrderPrice  float64 `json:"order_price,string"`
-	OrderSize   float64 `json:"order_size,string"`
-	StopPrice   string  `json:"stop_price,omitempty"`
-	Type        string  `json:"type"`
-	Timestamp   int     `json:"timestamp"`
-	RecvWindow  int     `json:"recvWindow,omitempty"`
+	OrderSymbol Market    `json:"order_symbol"`
+	OrderSide   OrderSide `json:"order_side"`
+	OrderPrice  Decimal   `json:"order_price"`
+	OrderSize   Decimal   `json:"order_size"`
+	StopPrice   *Decimal  `json:"stop_price,omitempty"`
+	Type        OrderType `json:"type"`
+	Timestamp   Timestamp `json:"timestamp"`
+	RecvWindow  int       `json:"recvWindow,omitempty"`
 }
 
-type NewOrderResp struct {
-	OrderID     string `json:"order_id"`
-	AccountID   string `json:"account_id"`
-	OrderSymbol string `json:"order_symbol"`
-	OrderSide   string `json:"order_side"`
-	Status      string `json:"status"`
-	CreateTime  int64  `json:"createTime"`
-	Type        string `json:"type"`
-	OrderPrice  string `json:"order_price"`
-	OrderSize   string `json:"order_size"`
-	Executed    string `json:"executed"`
-	StopPrice   string `json:"stop_price"`
-	Avg         string `json:"avg"`
-	Total       string `json:"total"`
-}
+type NewOrderResp = Order
 
 type TestNewOrderResp struct {
 	Result bool `json:"result"`
 }
 
 type OrderDetailRequest struct {
-	OrderID    string `json:"order_id"`
-	Timestamp  int64  `json:"timestamp"`
-	RecvWindow int64  `json:"recvWindow,omitempty"`
-}
-
-type OrderDetailResp struct {
-	OrderID     string  `json:"order_id"`
-	AccountID   string  `json:"account_id"`
-	OrderSymbol string  `json:"order_symbol"`
-	OrderSide   string  `json:"order_side"`
-	Status      string  `json:"status"`
-	CreateTime  int     `json:"createTime"`
-	Type        string  `json:"type"`
-	OrderPrice  float64 `json:"order_price,string"`
-	OrderSize   float64 `json:"order_size,string"`
-	Executed    float64 `json:"executed,string"`
-	StopPrice   float64 `json:"stop_price,string"`
-	Avg         float64 `json:"avg,string"`
-	Total       string  `json:"total"`
+	OrderID    string    `json:"order_id"`
+	Timestamp  Timestamp `json:"timestamp"`
+	RecvWindow int64     `json:"recvWindow,omitempty"`
 }
 
+type OrderDetailResp = Order
+
 type CancelOrderRequest struct {
-	OrderID     string `json:"order_id"`
-	OrderSymbol string `json:"order_symbol"`
-	Timestamp   int    `json:"timestamp"`
-	RecvWindow  int    `json:"recvWindow,omitempty"`
+	OrderID     string    `json:"order_id"`
+	OrderSymbol Market    `json:"order_symbol"`
+	Timestamp   Timestamp `json:"timestamp"`
+	RecvWindow  int       `json:"recvWindow,omitempty"`
 }
 
 type CancelOrderResp struct {
 	OrderID     string `json:"order_id"`
-	OrderSymbol string `json:"order_symbol"`
+	OrderSymbol Market `json:"order_symbol"`
 }
 
 type TradeDetailsRequest struct {
-	OrderID    string `json:"order_id"`
-	Timestamp  int64  `json:"timestamp"`
-	RecvWindow int64  `json:"recvWindow,omitempty"`
+	OrderID    string    `json:"order_id"`
+	Timestamp  Timestamp `json:"timestamp"`
+	RecvWindow int64     `json:"recvWindow,omitempty"`
 }
 
 type TradeDetailsResp []TradeDetailsRespElement
 
 type TradeDetailsRespElement struct {
-	HexID     string  `json:"hex_id"`
-	Symbol    string  `json:"symbol"`
-	OrderID   string  `json:"order_id"`
-	OrderSide string  `json:"order_side"`
-	Price     float64 `json:"price,string"`
-	Quantity  float64 `json:"quantity,string"`
-	Fee       string  `json:"fee"`
-	Total     string  `json:"total"`
-	Timestamp int     `json:"timestamp"`
+	HexID     string    `json:"hex_id"`
+	Symbol    Market    `json:"symbol"`
+	OrderID   string    `json:"order_id"`
+	OrderSide OrderSide `json:"order_side"`
+	Price     Decimal   `json:"price"`
+	Quantity  Decimal   `json:"quantity"`
+	Fee       Amount    `json:"fee"`
+	Total     Amount    `json:"total"`
+	Timestamp Timestamp `json:"timestamp"`
 }
 
 type OpenOrdersRequest struct {
-	Symbol     string `json:"symbol"`
-	FromID     string `json:"from_id,omitempty"`
-	Limit      int    `json:"limit,omitempty"`
-	Page       int    `json:"page"`
-	Timestamp  int    `json:"timestamp"`
-	RecvWindow int    `json:"recvWindow,omitempty"`
+	Symbol     Market    `json:"symbol"`
+	FromID     string    `json:"from_id,omitempty"`
+	Limit      int       `json:"limit,omitempty"`
+	Page       int       `json:"page"`
+	Timestamp  Timestamp `json:"timestamp"`
+	RecvWindow int       `json:"recvWindow,omitempty"`
 }
 
 type OpenOrdersResp struct {
@@ -107,29 +80,15 @@ type OpenOrdersResp struct {
 	List  []OpenOrdersRespElement `json:"list"`
 }
 
-type OpenOrdersRespElement struct {
-	OrderID     string  `json:"order_id"`
-	AccountID   string  `json:"account_id"`
-	OrderSymbol string  `json:"order_symbol"`
-	OrderSide   string  `json:"order_side"`
-	Status      string  `json:"status"`
-	CreateTime  int     `json:"createTime"`
-	Type        string  `json:"type"`
-	OrderPrice  float64 `json:"order_price,string"`
-	OrderSize   float64 `json:"order_size,string"`
-	Executed    float64 `json:"executed,string"`
-	StopPrice   float64 `json:"stop_price,string"`
-	Avg         float64 `json:"avg,string"`
-	Total       string  `json:"total"`
-}
+type OpenOrdersRespElement = Order
 
 type CompletedOrdersRequest struct {
-	Symbol     string `json:"symbol"`
-	FromID     string `json:"from_id,omitempty"`
-	Limit      int    `json:"limit,omitempty"`
-	Page       int    `json:"page"`
-	Timestamp  int    `json:"timestamp"`
-	RecvWindow int    `json:"recvWindow"`
+	Symbol     Market    `json:"symbol"`
+	FromID     string    `json:"from_id,omitempty"`
+	Limit      int       `json:"limit,omitempty"`
+	Page       int       `json:"page"`
+	Timestamp  Timestamp `json:"timestamp"`
+	RecvWindow int       `json:"recvWindow"`
 }
 
 type CompletedOrdersResp struct {
@@ -137,134 +96,165 @@ type CompletedOrdersResp struct {
 	List  []CompletedOrdersRespElement `json:"list"`
 }
 
-type CompletedOrdersRespElement struct {
-	OrderID     string  `json:"order_id"`
-	AccountID   string  `json:"account_id"`
-	OrderSymbol string  `json:"order_symbol"`
-	OrderSide   string  `json:"order_side"`
-	Status      string  `json:"status"`
-	CreateTime  int     `json:"createTime"`
-	Type        string  `json:"type"`
-	OrderPrice  float64 `json:"order_price,string"`
-	OrderSize   float64 `json:"order_size,string"`
-	Executed    float64 `json:"executed,string"`
-	StopPrice   float64 `json:"stop_price,string"`
-	Avg         float64 `json:"avg,string"`
-	Total       string  `json:"total"`
-}
+type CompletedOrdersRespElement = Order
 
 type AllOrdersRequest struct {
-	Symbol     string `json:"symbol"`
-	FromID     string `json:"from_id,omitempty"`
-	Limit      int64  `json:"limit,omitempty"`
-	Timestamp  int64  `json:"timestamp"`
-	RecvWindow int64  `json:"recvWindow,omitempty"`
+	Symbol     Market    `json:"symbol"`
+	FromID     string    `json:"from_id,omitempty"`
+	Limit      int64     `json:"limit,omitempty"`
+	Timestamp  Timestamp `json:"timestamp"`
+	RecvWindow int64     `json:"recvWindow,omitempty"`
 }
 
 type AllOrdersResp []AllOrdersRespElement
 
-type AllOrdersRespElement struct {
-	OrderID     string `json:"order_id"`
-	AccountID   string `json:"account_id"`
-	OrderSymbol string `json:"order_symbol"`
-	OrderSide   string `json:"order_side"`
-	Status      string `json:"status"`
-	CreateTime  int64  `json:"createTime"`
-	Type        string `json:"type"`
-	OrderPrice  string `json:"order_price"`
-	OrderSize   string `json:"order_size"`
-	Executed    string `json:"executed"`
-	StopPrice   string `json:"stop_price"`
-	Avg         string `json:"avg"`
-	Total       string `json:"total"`
-}
+type AllOrdersRespElement = Order
 
 type TradeListRequest struct {
-	Symbol     string `json:"symbol"`
-	FromID     string `json:"from_id,omitempty"`
-	Limit      int    `json:"limit,omitempty"`
-	Timestamp  int    `json:"timestamp"`
-	RecvWindow int    `json:"recvWindow,omitempty"`
+	Symbol     Market    `json:"symbol"`
+	FromID     string    `json:"from_id,omitempty"`
+	Limit      int       `json:"limit,omitempty"`
+	Timestamp  Timestamp `json:"timestamp"`
+	RecvWindow int       `json:"recvWindow,omitempty"`
 }
 
 type TradeListResp []TradeListRespElement
 
 type TradeListRespElement struct {
-	HexID     string `json:"hex_id"`
-	Symbol    string `json:"symbol"`
-	OrderID   string `json:"order_id"`
-	OrderSide string `json:"order_side"`
-	Price     string `json:"price"`
-	Quantity  string `json:"quantity"`
-	Fee       string `json:"fee"`
-	Total     string `json:"total"`
-	Timestamp int64  `json:"timestamp"`
+	HexID     string    `json:"hex_id"`
+	Symbol    Market    `json:"symbol"`
+	OrderID   string    `json:"order_id"`
+	OrderSide OrderSide `json:"order_side"`
+	Price     Decimal   `json:"price"`
+	Quantity  Decimal   `json:"quantity"`
+	Fee       Amount    `json:"fee"`
+	Total     Amount    `json:"total"`
+	Timestamp Timestamp `json:"timestamp"`
 }
 
 type AccountInformationRequest struct {
-	Timestamp  int `json:"timestamp"`
-	RecvWindow int `json:"recvWindow,omitempty"`
+	Timestamp  Timestamp `json:"timestamp"`
+	RecvWindow int       `json:"recvWindow,omitempty"`
 }
 
 type AccountInformationResp struct {
 	AccountID        string           `json:"account_id"`
 	Email            string           `json:"email"`
-	Phone            interface{}      `json:"phone"`
+	Phone            *string          `json:"phone"`
 	EnableGoogle2Fa  bool             `json:"enable_google_2fa"`
 	Status           string           `json:"status"`
-	CreateAt         int              `json:"create_at"`
+	CreateAt         Timestamp        `json:"create_at"`
 	NickName         string           `json:"nick_name"`
 	ChatID           string           `json:"chat_id"`
 	ChatPassword     string           `json:"chat_password"`
-	Banks            []interface{}    `json:"banks"`
+	Banks            []Bank           `json:"banks"`
 	Country          string           `json:"country"`
 	Language         string           `json:"language"`
-	KycStatus        interface{}      `json:"kyc_status"`
+	KycStatus        *string          `json:"kyc_status"`
 	KycLevel         string           `json:"kyc_level"`
 	LastLoginHistory LastLoginHistory `json:"last_login_history"`
 	CommissionStatus bool             `json:"commission_status"`
-	AccountKyc       interface{}      `json:"account_kyc"`
-	KycRejectInfos   []interface{}    `json:"kyc_reject_infos"`
+	AccountKyc       *AccountKyc      `json:"account_kyc"`
+	KycRejectInfos   []KycRejectInfo  `json:"kyc_reject_infos"`
 	AllowOrder       int              `json:"allow_order"`
 	DisableWithdraw  int              `json:"disable_withdraw"`
 	ReferralID       string           `json:"referral_id"`
-	FavoritePairs    []string         `json:"favorite_pairs"`
+	FavoritePairs    []Market         `json:"favorite_pairs"`
 	ChatServer       string           `json:"chat_server"`
 	ExchangeFee      ExchangeFee      `json:"exchange_fee"`
 }
 
 type ExchangeFee struct {
-	StandardFee float64 `json:"standard_fee,string"`
-	KnowFee     float64 `json:"know_fee,string"`
+	StandardFee Decimal `json:"standard_fee"`
+	KnowFee     Decimal `json:"know_fee"`
 }
 
 type LastLoginHistory struct {
-	ID          ID     `json:"id"`
-	AccountID   string `json:"account_id"`
-	NickName    string `json:"nick_name"`
-	Email       string `json:"email"`
-	IPAddress   string `json:"ip_address"`
-	LoginAt     int    `json:"login_at"`
-	OSName      string `json:"os_name"`
-	BrowserName string `json:"browser_name"`
-	Country     string `json:"country"`
-	City        string `json:"city"`
-	SentEmail   bool   `json:"sentEmail"`
+	ID          ID        `json:"id"`
+	AccountID   string    `json:"account_id"`
+	NickName    string    `json:"nick_name"`
+	Email       string    `json:"email"`
+	IPAddress   string    `json:"ip_address"`
+	LoginAt     Timestamp `json:"login_at"`
+	OSName      string    `json:"os_name"`
+	BrowserName string    `json:"browser_name"`
+	Country     string    `json:"country"`
+	City        string    `json:"city"`
+	SentEmail   bool      `json:"sentEmail"`
 }
 
 type ID struct {
-	Timestamp         int `json:"timestamp"`
-	MachineIdentifier int `json:"machineIdentifier"`
-	ProcessIdentifier int `json:"processIdentifier"`
-	Counter           int `json:"counter"`
-	Time              int `json:"time"`
-	Date              int `json:"date"`
-	TimeSecond        int `json:"timeSecond"`
+	Timestamp         int       `json:"timestamp"`
+	MachineIdentifier int       `json:"machineIdentifier"`
+	ProcessIdentifier int       `json:"processIdentifier"`
+	Counter           int       `json:"counter"`
+	Time              Timestamp `json:"time"`
+	Date              Timestamp `json:"date"`
+	TimeSecond        int       `json:"timeSecond"`
+}
+
+type Bank struct {
+	BankName      string `json:"bank_name"`
+	AccountName   string `json:"account_name"`
+	AccountNumber string `json:"account_number"`
+	SwiftCode     string `json:"swift_code"`
+	Country       string `json:"country"`
+}
+
+type AccountKyc struct {
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	Country        string    `json:"country"`
+	DocumentType   string    `json:"document_type"`
+	DocumentNumber string    `json:"document_number"`
+	Status         string    `json:"status"`
+	Level          string    `json:"level"`
+	SubmittedAt    Timestamp `json:"submitted_at"`
+}
+
+type KycRejectInfo struct {
+	Reason     string    `json:"reason"`
+	RejectedAt Timestamp `json:"rejected_at"`
+}
+
+// AccountCapabilities summarizes what an account is allowed to do.
+type AccountCapabilities struct {
+	Trading     bool
+	Withdrawals bool
+	// Restrictions explains why trading or withdrawals aren't allowed
+	Restrictions []string
+}
+
+// OrdersAllowed tells whether the account may place orders.
+func (r *AccountInformationResp) OrdersAllowed() bool {
+	return r.AllowOrder == 1
+}
+
+// WithdrawalsDisabled tells whether withdrawals are disabled for the account.
+func (r *AccountInformationResp) WithdrawalsDisabled() bool {
+	return r.DisableWithdraw != 0
+}
+
+// Capabilities returns what the account is allowed to do.
+func (r *AccountInformationResp) Capabilities() AccountCapabilities {
+	capabilities := AccountCapabilities{Trading: r.OrdersAllowed(), Withdrawals: !r.WithdrawalsDisabled()}
+	if !capabilities.Trading {
+		capabilities.Restrictions = append(capabilities.Restrictions, "placing orders is not allowed")
+	}
+	if !capabilities.Withdrawals {
+		capabilities.Restrictions = append(capabilities.Restrictions, "withdrawals are disabled")
+	}
+	return capabilities
+}
+
+// CanTrade tells whether the account may place orders, check it before trading.
+func (r *AccountInformationResp) CanTrade() bool {
+	return r.Capabilities().Trading
 }
 
 type AccountBalancesRequest struct {
-	Timestamp  int `json:"timestamp"`
-	RecvWindow int `json:"recvWindow,omitempty"`
+	Timestamp  Timestamp `json:"timestamp"`
+	RecvWindow int       `json:"recvWindow,omitempty"`
 }
 
 type AccountBalancesResp []AccountBalancesRespElement
@@ -272,315 +262,524 @@ type AccountBalancesResp []AccountBalancesRespElement
 type AccountBalancesRespElement struct {
 	CurrencyCode string  `json:"currency_code"`
 	Address      string  `json:"address"`
-	Total        float64 `json:"total,string"`
-	Available    float64 `json:"available,string"`
-	InOrder      float64 `json:"in_order,string"`
+	Total        Decimal `json:"total"`
+	Available    Decimal `json:"available"`
+	InOrder      Decimal `json:"in_order"`
 }
 
-func (c *client) NewOrder(request *NewOrderRequest) (*NewOrderResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/add", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
+// Validate checks that the request is complete, errors wrap ErrInvalidParams.
+func (r *NewOrderRequest) Validate() error {
+	switch {
+	case r.OrderSymbol == "":
+		return fmt.Errorf("%w: order symbol is missing", ErrInvalidParams)
+	case !r.OrderSide.IsValid():
+		return fmt.Errorf("%w: invalid order side %q", ErrInvalidParams, r.OrderSide)
+	case !r.Type.IsValid():
+		return fmt.Errorf("%w: invalid order type %q", ErrInvalidParams, r.Type)
+	case r.OrderSize.Sign() <= 0:
+		return fmt.Errorf("%w: order size must be positive, got %s", ErrInvalidParams, r.OrderSize)
 	}
-	resp, err := c.sendPost(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
+	switch canonical(string(r.Type), orderTypes) {
+	case OrderTypeLimit, OrderTypeStopLimit:
+		if r.OrderPrice.Sign() <= 0 {
+			return fmt.Errorf("%w: order price must be positive, got %s", ErrInvalidParams, r.OrderPrice)
+		}
+	case OrderTypeMarket:
+		if !r.OrderPrice.IsZero() {
+			return fmt.Errorf("%w: market orders can't have a price", ErrInvalidParams)
+		}
 	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
+	if canonical(string(r.Type), orderTypes) == OrderTypeStopLimit {
+		if r.StopPrice == nil || r.StopPrice.Sign() <= 0 {
+			return fmt.Errorf("%w: stop limit order needs a positive stop price", ErrInvalidParams)
+		}
+	} else if r.StopPrice != nil {
+		return fmt.Errorf("%w: stop price is only allowed for stop limit orders", ErrInvalidParams)
 	}
+	return nil
+}
 
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+func (r NewOrderRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	r.OrderSymbol = Market(r.OrderSymbol.Symbol())
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
 	}
+	if r.RecvWindow == 0 {
+		r.RecvWindow = int(recvWindow)
+	}
+	return r
+}
 
-	var result NewOrderResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
-		return nil, err
+func (r NewOrderRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r OrderDetailRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
 	}
-	return &result, nil
+	if r.RecvWindow == 0 {
+		r.RecvWindow = recvWindow
+	}
+	return r
 }
 
-func (c *client) TestNewOrder(request *NewOrderRequest) (*TestNewOrderResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/test", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
+func (r OrderDetailRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r CancelOrderRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	r.OrderSymbol = Market(r.OrderSymbol.Symbol())
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
 	}
-	resp, err := c.sendPost(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
+	if r.RecvWindow == 0 {
+		r.RecvWindow = int(recvWindow)
 	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
+	return r
+}
+
+func (r CancelOrderRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r TradeDetailsRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
+	}
+	if r.RecvWindow == 0 {
+		r.RecvWindow = recvWindow
 	}
+	return r
+}
 
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+func (r TradeDetailsRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r OpenOrdersRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	r.Symbol = Market(r.Symbol.Symbol())
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
+	}
+	if r.RecvWindow == 0 {
+		r.RecvWindow = int(recvWindow)
 	}
+	return r
+}
 
-	var result TestNewOrderResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
-		return nil, err
+func (r OpenOrdersRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r CompletedOrdersRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	r.Symbol = Market(r.Symbol.Symbol())
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
 	}
-	return &result, nil
+	if r.RecvWindow == 0 {
+		r.RecvWindow = int(recvWindow)
+	}
+	return r
 }
 
-func (c *client) OrderDetail(request *OrderDetailRequest) (*OrderDetailResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/details", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
+func (r CompletedOrdersRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r AllOrdersRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	r.Symbol = Market(r.Symbol.Symbol())
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
 	}
-	resp, err := c.sendPost(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
+	if r.RecvWindow == 0 {
+		r.RecvWindow = recvWindow
 	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
+	return r
+}
+
+func (r AllOrdersRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r TradeListRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	r.Symbol = Market(r.Symbol.Symbol())
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
+	}
+	if r.RecvWindow == 0 {
+		r.RecvWindow = int(recvWindow)
 	}
+	return r
+}
 
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+func (r TradeListRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r AccountInformationRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
+	}
+	if r.RecvWindow == 0 {
+		r.RecvWindow = int(recvWindow)
 	}
+	return r
+}
 
-	var result OrderDetailResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
-		return nil, err
+func (r AccountInformationRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (r AccountBalancesRequest) withTimestamp(timestamp int64, recvWindow int64) interface{} {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = NewTimestampFromMillis(timestamp)
 	}
-	return &result, nil
+	if r.RecvWindow == 0 {
+		r.RecvWindow = int(recvWindow)
+	}
+	return r
 }
 
-func (c *client) CancelOrder(request *CancelOrderRequest) (*CancelOrderResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/cancel", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
+func (r AccountBalancesRequest) hasTimestamp() bool {
+	return !r.Timestamp.IsZero()
+}
+
+func (c *client) NewOrder(request *NewOrderRequest) (*NewOrderResp, error) {
+	return c.NewOrderContext(context.Background(), request)
+}
+
+func (c *client) NewOrderContext(ctx context.Context, request *NewOrderRequest) (*NewOrderResp, error) {
+	if err := request.Validate(); err != nil {
 		return nil, err
 	}
-	resp, err := c.sendDelete(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
+	if c.validators != nil {
+		validator, err := c.validators(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting order rules, %w", err)
+		}
+		if err := validator.Check(request); err != nil {
+			return nil, err
+		}
 	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
+	url := fmt.Sprintf("%s/api/v2/order/add", c.accountAPIEndpoint)
+	var confirmed *NewOrderResp
+	resp, err := c.sendSigned(ctx, request, func(payload interface{}) (*response, error) {
+		stamped := payload.(NewOrderRequest)
+		return c.sendUnconfirmed(ctx, func() (*response, error) {
+			return c.sendJSON(ctx, endpointNewOrder, http.MethodPost, url, stamped)
+		}, func() (bool, error) {
+			var err error
+			confirmed, err = c.confirmNewOrder(ctx, &stamped)
+			return confirmed != nil, err
+		})
+	})
+	if confirmed != nil {
+		return confirmed, nil
 	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	var result CancelOrderResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	var result NewOrderResp
+	if err := c.decodeResponse(resp, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-func (c *client) TradeDetails(request *TradeDetailsRequest) (*TradeDetailsResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/trade-detail", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.sendPost(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
+func (c *client) TestNewOrder(request *NewOrderRequest) (*TestNewOrderResp, error) {
+	return c.TestNewOrderContext(context.Background(), request)
+}
+
+func (c *client) TestNewOrderContext(ctx context.Context, request *NewOrderRequest) (*TestNewOrderResp, error) {
+	if err := request.Validate(); err != nil {
 		return nil, err
 	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
+	url := fmt.Sprintf("%s/api/v2/order/test", c.accountAPIEndpoint)
+	var result TestNewOrderResp
+	if err := c.signed(ctx, endpointTestNewOrder, http.MethodPost, url, request, &result); err != nil {
 		return nil, err
 	}
+	return &result, nil
+}
 
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+func (c *client) OrderDetail(request *OrderDetailRequest) (*OrderDetailResp, error) {
+	return c.OrderDetailContext(context.Background(), request)
+}
 
-	var result TradeDetailsResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+func (c *client) OrderDetailContext(ctx context.Context, request *OrderDetailRequest) (*OrderDetailResp, error) {
+	url := fmt.Sprintf("%s/api/v2/order/details", c.accountAPIEndpoint)
+	var result OrderDetailResp
+	if err := c.signed(ctx, endpointOrderDetail, http.MethodPost, url, request, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-func (c *client) OpenOrders(request *OpenOrdersRequest) (*OpenOrdersResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/list/open", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
+func (c *client) CancelOrder(request *CancelOrderRequest) (*CancelOrderResp, error) {
+	return c.CancelOrderContext(context.Background(), request)
+}
+
+func (c *client) CancelOrderContext(ctx context.Context, request *CancelOrderRequest) (*CancelOrderResp, error) {
+	url := fmt.Sprintf("%s/api/v2/order/cancel", c.accountAPIEndpoint)
+	var confirmed *CancelOrderResp
+	resp, err := c.sendSigned(ctx, request, func(payload interface{}) (*response, error) {
+		stamped := payload.(CancelOrderRequest)
+		return c.sendUnconfirmed(ctx, func() (*response, error) {
+			return c.sendJSON(ctx, endpointCancelOrder, http.MethodDelete, url, stamped)
+		}, func() (bool, error) {
+			var err error
+			confirmed, err = c.confirmCancelOrder(ctx, &stamped)
+			return confirmed != nil, err
+		})
+	})
+	if confirmed != nil {
+		return confirmed, nil
 	}
-	resp, err := c.sendPost(url, nil, bytes.NewReader(asJSON))
 	if err != nil {
 		return nil, err
 	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
+
+	var result CancelOrderResp
+	if err := c.decodeResponse(resp, &result); err != nil {
 		return nil, err
 	}
+	return &result, nil
+}
 
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+func (c *client) TradeDetails(request *TradeDetailsRequest) (*TradeDetailsResp, error) {
+	return c.TradeDetailsContext(context.Background(), request)
+}
+
+func (c *client) TradeDetailsContext(ctx context.Context, request *TradeDetailsRequest) (*TradeDetailsResp, error) {
+	url := fmt.Sprintf("%s/api/v2/order/trade-detail", c.accountAPIEndpoint)
+	var result TradeDetailsResp
+	if err := c.signed(ctx, endpointTradeDetails, http.MethodPost, url, request, &result); err != nil {
 		return nil, err
 	}
+	return &result, nil
+}
+
+func (c *client) OpenOrders(request *OpenOrdersRequest) (*OpenOrdersResp, error) {
+	return c.OpenOrdersContext(context.Background(), request)
+}
 
+func (c *client) OpenOrdersContext(ctx context.Context, request *OpenOrdersRequest) (*OpenOrdersResp, error) {
+	url := fmt.Sprintf("%s/api/v2/order/list/open", c.accountAPIEndpoint)
 	var result OpenOrdersResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.signed(ctx, endpointOpenOrders, http.MethodPost, url, request, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 func (c *client) CompletedOrders(request *CompletedOrdersRequest) (*CompletedOrdersResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/list/completed", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.sendPost(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return c.CompletedOrdersContext(context.Background(), request)
+}
 
+func (c *client) CompletedOrdersContext(ctx context.Context, request *CompletedOrdersRequest) (*CompletedOrdersResp, error) {
+	url := fmt.Sprintf("%s/api/v2/order/list/completed", c.accountAPIEndpoint)
 	var result CompletedOrdersResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.signed(ctx, endpointCompletedOrders, http.MethodPost, url, request, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 func (c *client) AllOrders(request *AllOrdersRequest) (*AllOrdersResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/list/all", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.sendPost(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return c.AllOrdersContext(context.Background(), request)
+}
 
+func (c *client) AllOrdersContext(ctx context.Context, request *AllOrdersRequest) (*AllOrdersResp, error) {
+	url := fmt.Sprintf("%s/api/v2/order/list/all", c.accountAPIEndpoint)
 	var result AllOrdersResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.signed(ctx, endpointAllOrders, http.MethodPost, url, request, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 func (c *client) TradeList(request *TradeListRequest) (*TradeListResp, error) {
-	url := fmt.Sprintf("%s/api/v2/order/list/trades", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.sendPost(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return c.TradeListContext(context.Background(), request)
+}
 
+func (c *client) TradeListContext(ctx context.Context, request *TradeListRequest) (*TradeListResp, error) {
+	url := fmt.Sprintf("%s/api/v2/order/list/trades", c.accountAPIEndpoint)
 	var result TradeListResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.signed(ctx, endpointTradeList, http.MethodPost, url, request, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 func (c *client) AccountInformation(request *AccountInformationRequest) (*AccountInformationResp, error) {
-	url := fmt.Sprintf("%s/api/v2/account/details", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.sendGet(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return c.AccountInformationContext(context.Background(), request)
+}
 
+func (c *client) AccountInformationContext(ctx context.Context, request *AccountInformationRequest) (*AccountInformationResp, error) {
+	url := fmt.Sprintf("%s/api/v2/account/details", c.accountAPIEndpoint)
 	var result AccountInformationResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.signed(ctx, endpointAccountInformation, http.MethodGet, url, request, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 func (c *client) AccountBalances(request *AccountBalancesRequest) (*AccountBalancesResp, error) {
+	return c.AccountBalancesContext(context.Background(), request)
+}
+
+func (c *client) AccountBalancesContext(ctx context.Context, request *AccountBalancesRequest) (*AccountBalancesResp, error) {
 	url := fmt.Sprintf("%s/api/v2/account/balances", c.accountAPIEndpoint)
-	asJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.sendGet(url, nil, bytes.NewReader(asJSON))
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
+	var result AccountBalancesResp
+	if err := c.signed(ctx, endpointAccountBalances, http.MethodGet, url, request, &result); err != nil {
 		return nil, err
 	}
+	return &result, nil
+}
 
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// confirmNewOrder looks for the order placed by request in the open and completed orders. It returns
+// nil if the order provably has not been placed.
+func (c *client) confirmNewOrder(ctx context.Context, request *NewOrderRequest) (*NewOrderResp, error) {
+	// the exchange dates the order by its own clock, which may be behind ours by up to the measured
+	// offset, and it accepts timestamps ahead of it. Orders created in that slack may or may not be ours.
+	created := request.Timestamp.Millis() - c.clockOffset().Milliseconds()
+	earliest := created - int64(request.RecvWindow) - exchangeFutureSkew.Milliseconds()
+
+	open, openComplete, err := findNewOrders(request, earliest, func(page int) (int, []Order, error) {
+		resp, err := c.OpenOrdersContext(ctx, &OpenOrdersRequest{
+			Symbol:     request.OrderSymbol,
+			Limit:      confirmOrdersLimit,
+			Page:       page,
+			RecvWindow: request.RecvWindow,
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+		return resp.Total, resp.List, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	completed, completedComplete, err := findNewOrders(request, earliest, func(page int) (int, []Order, error) {
+		resp, err := c.CompletedOrdersContext(ctx, &CompletedOrdersRequest{
+			Symbol:     request.OrderSymbol,
+			Limit:      confirmOrdersLimit,
+			Page:       page,
+			RecvWindow: request.RecvWindow,
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+		return resp.Total, resp.List, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches, uncertain []*Order
+	seen := map[string]bool{}
+	for _, order := range append(open, completed...) {
+		if seen[order.OrderID] {
+			continue
+		}
+		seen[order.OrderID] = true
+		if order.CreateTime.Millis() >= created {
+			matches = append(matches, order)
+		} else {
+			uncertain = append(uncertain, order)
+		}
+	}
+	switch {
+	case len(matches) == 1 && len(uncertain) == 0:
+		return matches[0], nil
+	case len(matches)+len(uncertain) > 1:
+		return nil, &outcomeUnknownError{err: fmt.Errorf("%d orders match the new order, can't tell which one was placed", len(matches)+len(uncertain))}
+	case len(uncertain) == 1:
+		return nil, &outcomeUnknownError{err: fmt.Errorf("order %s matches the new order but may have been created before it", uncertain[0].OrderID)}
+	case !openComplete || !completedComplete:
+		return nil, &outcomeUnknownError{err: errors.New("too many orders to confirm the new order wasn't placed")}
+	default:
+		return nil, nil
+	}
+}
+
+// findNewOrders pages through a list of orders fetched by fetch and returns the orders matching
+// request created at or after earliest. complete is false if the list couldn't be searched to its end.
+func findNewOrders(request *NewOrderRequest, earliest int64, fetch func(page int) (total int, list []Order, err error)) (found []*Order, complete bool, err error) {
+	read := 0
+	for page := 0; page < confirmOrdersPages; page++ {
+		total, list, err := fetch(page)
+		if err != nil {
+			return nil, false, err
+		}
+		for i := range list {
+			if matchesNewOrder(request, &list[i], earliest) {
+				found = append(found, &list[i])
+			}
+		}
+		read += len(list)
+		if read >= total {
+			return found, true, nil
+		}
+		// the list ended short of its total
+		if len(list) == 0 {
+			return found, false, nil
+		}
+		// the rest of a list sorted newest first is too old
+		if newestFirst(list) && list[len(list)-1].CreateTime.Millis() < earliest {
+			return found, true, nil
+		}
+	}
+	return found, false, nil
+}
+
+func newestFirst(list []Order) bool {
+	for i := 1; i < len(list); i++ {
+		if list[i].CreateTime.Millis() > list[i-1].CreateTime.Millis() {
+			return false
+		}
+	}
+	return true
+}
+
+// confirmCancelOrder checks the state of the order to cancel. It returns nil if the order is still open.
+func (c *client) confirmCancelOrder(ctx context.Context, request *CancelOrderRequest) (*CancelOrderResp, error) {
+	detail, err := c.OrderDetailContext(ctx, &OrderDetailRequest{
+		OrderID:    request.OrderID,
+		RecvWindow: int64(request.RecvWindow),
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch detail.Status {
+	case OrderStatusCanceled:
+		return &CancelOrderResp{OrderID: detail.OrderID, OrderSymbol: detail.OrderSymbol}, nil
+	case OrderStatusOpen, OrderStatusPartiallyFilled:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("order %s can not be canceled, status is %s", detail.OrderID, detail.Status)
+	}
+}
+
+// number of orders per page and pages looked at when confirming a new order
+const (
+	confirmOrdersLimit = 50
+	confirmOrdersPages = 5
+)
 
-	var result AccountBalancesResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
-		return nil, err
+// how far ahead of the exchange's clock a request's timestamp may be
+const exchangeFutureSkew = time.Second
+
+// matchesNewOrder tells whether order has the values of request and was created at or after earliest, in ms.
+func matchesNewOrder(request *NewOrderRequest, order *Order, earliest int64) bool {
+	if !order.OrderSymbol.Equal(request.OrderSymbol) || !strings.EqualFold(string(order.OrderSide), string(request.OrderSide)) ||
+		!order.OrderSize.Equal(request.OrderSize) {
+		return false
 	}
-	return &result, nil
+	if !request.OrderPrice.IsZero() && !order.OrderPrice.Equal(request.OrderPrice) {
+		return false
+	}
+	return order.CreateTime.Millis() >= earliest
 }
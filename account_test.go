@@ -66,18 +66,21 @@ func TestNewOrder(t *testing.T) {
 	request := &NewOrderRequest{
 		OrderSymbol: "KNOW_ETH",
 		OrderSide:   "BUY",
-		OrderPrice:  0.0000123,
-		OrderSize:   7777,
+		OrderPrice:  MustParseDecimal("0.0000123"),
+		OrderSize:   NewDecimalFromInt(7777),
 		Type:        "LIMIT",
-		Timestamp:   1507725176599,
+		Timestamp:   NewTimestampFromMillis(1507725176599),
 		RecvWindow:  5000,
 	}
 	resp, err := client.NewOrder(request)
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
 
+	assert.Equal(t, OrderTypeLimit, resp.Type)
+
+	// the type is encoded in its canonical spelling
 	respBytes, _ := json.Marshal(resp)
-	equal, _ := isEqualJSON(body, string(respBytes))
+	equal, _ := isEqualJSONFold(body, string(respBytes), "type")
 	assert.True(t, equal, fmt.Sprintf("%s is not equal to %s", body, string(respBytes)))
 }
 
@@ -123,10 +126,10 @@ func TestTestNewOrder(t *testing.T) {
 	request := &NewOrderRequest{
 		OrderSymbol: "KNOW_ETH",
 		OrderSide:   "BUY",
-		OrderPrice:  0.0000123,
-		OrderSize:   7777,
+		OrderPrice:  MustParseDecimal("0.0000123"),
+		OrderSize:   NewDecimalFromInt(7777),
 		Type:        "LIMIT",
-		Timestamp:   1507725176599,
+		Timestamp:   NewTimestampFromMillis(1507725176599),
 		RecvWindow:  5000,
 	}
 	resp, err := client.TestNewOrder(request)
@@ -187,15 +190,18 @@ func TestOrderDetail(t *testing.T) {
 	}
 	request := &OrderDetailRequest{
 		OrderID:    "0e3f05e0-912c-4957-9322-d1a34ef6e312",
-		Timestamp:  1429514463299,
+		Timestamp:  NewTimestampFromMillis(1429514463299),
 		RecvWindow: 5000,
 	}
 	resp, err := client.OrderDetail(request)
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
 
+	assert.Equal(t, OrderTypeLimit, resp.Type)
+
+	// the type is encoded in its canonical spelling
 	respBytes, _ := json.Marshal(resp)
-	equal, _ := isEqualJSON(body, string(respBytes))
+	equal, _ := isEqualJSONFold(body, string(respBytes), "type")
 	assert.True(t, equal, fmt.Sprintf("%s is not equal to %s", body, string(respBytes)))
 }
 
@@ -239,7 +245,7 @@ func TestCancelOrder(t *testing.T) {
 	request := &CancelOrderRequest{
 		OrderID:     "02140bef-0c98-4997-9412-9e7ca6f1cc0e",
 		OrderSymbol: "KNOW_ETH",
-		Timestamp:   1429514463299,
+		Timestamp:   NewTimestampFromMillis(1429514463299),
 		RecvWindow:  5000,
 	}
 	resp, err := client.CancelOrder(request)
@@ -298,7 +304,7 @@ func TestTradeDetails(t *testing.T) {
 	}
 	request := &TradeDetailsRequest{
 		OrderID:    "08098511-ae65-452b-9a84-5b79a5160b5f",
-		Timestamp:  1429514463299,
+		Timestamp:  NewTimestampFromMillis(1429514463299),
 		RecvWindow: 5000,
 	}
 	resp, err := client.TradeDetails(request)
@@ -368,15 +374,18 @@ func TestOpenOrders(t *testing.T) {
 		Limit:      10,
 		Page:       0,
 		Symbol:     "KNOW_BTC",
-		Timestamp:  1429514463299,
+		Timestamp:  NewTimestampFromMillis(1429514463299),
 		RecvWindow: 5000,
 	}
 	resp, err := client.OpenOrders(request)
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
 
+	assert.Equal(t, OrderTypeLimit, resp.List[0].Type)
+
+	// the type is encoded in its canonical spelling
 	respBytes, _ := json.Marshal(resp)
-	equal, _ := isEqualJSON(body, string(respBytes))
+	equal, _ := isEqualJSONFold(body, string(respBytes), "type")
 	assert.True(t, equal, fmt.Sprintf("%s is not equal to %s", body, string(respBytes)))
 }
 
@@ -438,15 +447,18 @@ func TestCompletedOrders(t *testing.T) {
 		Limit:      10,
 		Page:       0,
 		Symbol:     "KNOW_BTC",
-		Timestamp:  1429514463299,
+		Timestamp:  NewTimestampFromMillis(1429514463299),
 		RecvWindow: 5000,
 	}
 	resp, err := client.CompletedOrders(request)
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
 
+	assert.Equal(t, OrderTypeLimit, resp.List[0].Type)
+
+	// the type is encoded in its canonical spelling
 	respBytes, _ := json.Marshal(resp)
-	equal, _ := isEqualJSON(body, string(respBytes))
+	equal, _ := isEqualJSONFold(body, string(respBytes), "type")
 	assert.True(t, equal, fmt.Sprintf("%s is not equal to %s", body, string(respBytes)))
 }
 
@@ -503,15 +515,18 @@ func TestAllOrders(t *testing.T) {
 	request := &AllOrdersRequest{
 		Symbol:     "KNOW_BTC",
 		Limit:      50,
-		Timestamp:  1530682938651,
+		Timestamp:  NewTimestampFromMillis(1530682938651),
 		RecvWindow: 5000,
 	}
 	resp, err := client.AllOrders(request)
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
 
+	assert.Equal(t, OrderTypeLimit, (*resp)[0].Type)
+
+	// the type is encoded in its canonical spelling
 	respBytes, _ := json.Marshal(resp)
-	equal, _ := isEqualJSON(body, string(respBytes))
+	equal, _ := isEqualJSONFold(body, string(respBytes), "type")
 	assert.True(t, equal, fmt.Sprintf("%s is not equal to %s", body, string(respBytes)))
 }
 
@@ -564,7 +579,7 @@ func TestTradeList(t *testing.T) {
 	request := &TradeListRequest{
 		Symbol:     "KNOW_BTC",
 		Limit:      50,
-		Timestamp:  1530682938651,
+		Timestamp:  NewTimestampFromMillis(1530682938651),
 		RecvWindow: 5000,
 	}
 	resp, err := client.TradeList(request)
@@ -659,7 +674,7 @@ func TestAccountInformation(t *testing.T) {
 		t.Error(err.Error())
 	}
 	request := &AccountInformationRequest{
-		Timestamp:  1530682938651,
+		Timestamp:  NewTimestampFromMillis(1530682938651),
 		RecvWindow: 5000,
 	}
 	resp, err := client.AccountInformation(request)
@@ -712,7 +727,7 @@ func TestAccountBalances(t *testing.T) {
 		t.Error(err.Error())
 	}
 	request := &AccountBalancesRequest{
-		Timestamp:  1530682938651,
+		Timestamp:  NewTimestampFromMillis(1530682938651),
 		RecvWindow: 5000,
 	}
 	resp, err := client.AccountBalances(request)
@@ -723,3 +738,34 @@ func TestAccountBalances(t *testing.T) {
 	equal, _ := isEqualJSON(body, string(respBytes))
 	assert.True(t, equal, fmt.Sprintf("%s is not equal to %s", body, string(respBytes)))
 }
+
+func TestAccountInformationTyped(t *testing.T) {
+	body := `{
+		"phone": "+1 555 0100",
+		"kyc_status": "approved",
+		"banks": [{"bank_name": "Bank", "account_name": "Name", "account_number": "123", "swift_code": "ABCDEF12", "country": "US"}],
+		"account_kyc": {"first_name": "First", "last_name": "Last", "status": "approved", "level": "level2", "submitted_at": 1524567654822},
+		"kyc_reject_infos": [{"reason": "blurry document", "rejected_at": 1524567654000}],
+		"allow_order": 0,
+		"disable_withdraw": 1
+	}`
+	var resp AccountInformationResp
+	assert.Nil(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, "+1 555 0100", *resp.Phone)
+	assert.Equal(t, "approved", *resp.KycStatus)
+	assert.Equal(t, "123", resp.Banks[0].AccountNumber)
+	assert.Equal(t, "level2", resp.AccountKyc.Level)
+	assert.Equal(t, int64(1524567654822), resp.AccountKyc.SubmittedAt.Millis())
+	assert.Equal(t, "blurry document", resp.KycRejectInfos[0].Reason)
+
+	assert.False(t, resp.OrdersAllowed())
+	assert.True(t, resp.WithdrawalsDisabled())
+	assert.False(t, resp.CanTrade())
+	assert.Equal(t, AccountCapabilities{
+		Restrictions: []string{"placing orders is not allowed", "withdrawals are disabled"},
+	}, resp.Capabilities())
+
+	resp.AllowOrder, resp.DisableWithdraw = 1, 0
+	assert.True(t, resp.CanTrade())
+	assert.Equal(t, AccountCapabilities{Trading: true, Withdrawals: true}, resp.Capabilities())
+}
@@ -0,0 +1,86 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Amount is a value in a currency, as sent by the exchange in fields like "0.09565710 ETH".
+// Text in other formats is kept as is, see Parsed and String.
+type Amount struct {
+	Value    Decimal
+	Currency string
+	// the original text if it couldn't be parsed
+	raw string
+}
+
+// NewAmount returns value in currency.
+func NewAmount(value Decimal, currency string) Amount {
+	return Amount{Value: value, Currency: currency}
+}
+
+// ParseAmount parses a value optionally followed by a currency code, like "0.09565710 ETH".
+func ParseAmount(s string) (Amount, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+	value, err := ParseDecimal(fields[0])
+	if err != nil {
+		return Amount{}, err
+	}
+	amount := Amount{Value: value}
+	if len(fields) == 2 {
+		amount.Currency = strings.ToUpper(fields[1])
+	}
+	return amount, nil
+}
+
+// Parsed tells whether the amount was parsed, if it wasn't Value and Currency are unset
+// and String returns the original text.
+func (a Amount) Parsed() bool {
+	return a.raw == ""
+}
+
+// String returns the amount as "value currency", or the original text if it couldn't be parsed.
+func (a Amount) String() string {
+	if a.raw != "" {
+		return a.raw
+	}
+	if a.Currency == "" {
+		return a.Value.String()
+	}
+	return a.Value.String() + " " + a.Currency
+}
+
+// MarshalJSON encodes the amount as string.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON decodes a JSON string or number. Strings in an unexpected format don't fail,
+// they are kept as is.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		// a bare number
+		var value Decimal
+		if err := value.UnmarshalJSON(data); err != nil {
+			return err
+		}
+		*a = Amount{Value: value}
+		return nil
+	}
+	if strings.TrimSpace(s) == "" {
+		*a = Amount{}
+		return nil
+	}
+	amount, err := ParseAmount(s)
+	if err != nil {
+		*a = Amount{raw: s}
+		return nil
+	}
+	*a = amount
+	return nil
+}
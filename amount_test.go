@@ -0,0 +1,48 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	amount, err := ParseAmount("0.09565710 ETH")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.09565710", amount.Value.String())
+	assert.Equal(t, "ETH", amount.Currency)
+	assert.Equal(t, "0.09565710 ETH", amount.String())
+
+	amount, err = ParseAmount("1000.00275")
+	assert.Nil(t, err)
+	assert.Equal(t, "1000.00275", amount.Value.String())
+	assert.Equal(t, "", amount.Currency)
+
+	for _, input := range []string{"", "ETH", "1 2 ETH", "ETH 0.1"} {
+		_, err := ParseAmount(input)
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var trade TradeListRespElement
+	err := json.Unmarshal([]byte(`{"fee": "0.37449524 know", "total": "about 5 BTC"}`), &trade)
+	assert.Nil(t, err)
+	assert.True(t, trade.Fee.Parsed())
+	assert.Equal(t, "0.37449524", trade.Fee.Value.String())
+	assert.Equal(t, "KNOW", trade.Fee.Currency)
+	assert.False(t, trade.Total.Parsed())
+	assert.Equal(t, "about 5 BTC", trade.Total.String())
+	assert.True(t, trade.Total.Value.IsZero())
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"fee": 0.5, "total": null}`), &trade))
+	assert.Equal(t, "0.5", trade.Fee.String())
+	assert.True(t, trade.Total.Value.IsZero())
+
+	output, err := json.Marshal(NewAmount(MustParseDecimal("0.05750073"), "BTC"))
+	assert.Nil(t, err)
+	assert.Equal(t, `"0.05750073 BTC"`, string(output))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"fee": true}`), &trade))
+}
@@ -0,0 +1,81 @@
+package kryptono
+
+import "time"
+
+// OrderBuilder builds a NewOrderRequest for NewOrder or TestNewOrder, e.g.
+//
+//	request, err := kryptono.LimitOrder("KNOW_BTC").Buy().Price(price).Size(size).Build()
+type OrderBuilder struct {
+	request NewOrderRequest
+}
+
+// LimitOrder starts a limit order in market, it needs a side, price and size.
+func LimitOrder(market Market) *OrderBuilder {
+	return &OrderBuilder{request: NewOrderRequest{OrderSymbol: market, Type: OrderTypeLimit}}
+}
+
+// MarketOrder starts a market order in market, it needs a side and size but no price.
+func MarketOrder(market Market) *OrderBuilder {
+	return &OrderBuilder{request: NewOrderRequest{OrderSymbol: market, Type: OrderTypeMarket}}
+}
+
+// StopLimitOrder starts a stop limit order in market, it needs a side, stop price, price and size.
+func StopLimitOrder(market Market) *OrderBuilder {
+	return &OrderBuilder{request: NewOrderRequest{OrderSymbol: market, Type: OrderTypeStopLimit}}
+}
+
+// Buy makes the order buy.
+func (b *OrderBuilder) Buy() *OrderBuilder {
+	return b.Side(OrderSideBuy)
+}
+
+// Sell makes the order sell.
+func (b *OrderBuilder) Sell() *OrderBuilder {
+	return b.Side(OrderSideSell)
+}
+
+// Side sets the side of the order.
+func (b *OrderBuilder) Side(side OrderSide) *OrderBuilder {
+	b.request.OrderSide = side
+	return b
+}
+
+// Price sets the limit price of the order.
+func (b *OrderBuilder) Price(price Decimal) *OrderBuilder {
+	b.request.OrderPrice = price
+	return b
+}
+
+// Size sets the size of the order in the base currency.
+func (b *OrderBuilder) Size(size Decimal) *OrderBuilder {
+	b.request.OrderSize = size
+	return b
+}
+
+// StopPrice sets the price triggering a stop limit order.
+func (b *OrderBuilder) StopPrice(price Decimal) *OrderBuilder {
+	b.request.StopPrice = &price
+	return b
+}
+
+// Timestamp sets the timestamp of the request, by default the client fills it in when sending.
+func (b *OrderBuilder) Timestamp(t time.Time) *OrderBuilder {
+	b.request.Timestamp = NewTimestamp(t)
+	return b
+}
+
+// RecvWindow sets how long after its timestamp the request is valid, by default the client's recvWindow is used.
+func (b *OrderBuilder) RecvWindow(recvWindow time.Duration) *OrderBuilder {
+	b.request.RecvWindow = int(recvWindow / time.Millisecond)
+	return b
+}
+
+// Build returns the request, or an error wrapping ErrInvalidParams if it is incomplete or
+// combines things that don't go together, like a market order with a price.
+func (b *OrderBuilder) Build() (*NewOrderRequest, error) {
+	request := b.request
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
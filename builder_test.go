@@ -0,0 +1,88 @@
+package kryptono
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderBuilders(t *testing.T) {
+	price := MustParseDecimal("0.0000123")
+	size := NewDecimalFromInt(7777)
+
+	limit, err := LimitOrder("KNOW_ETH").Buy().Price(price).Size(size).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, &NewOrderRequest{OrderSymbol: "KNOW_ETH", OrderSide: OrderSideBuy, Type: OrderTypeLimit, OrderPrice: price, OrderSize: size}, limit)
+
+	market, err := MarketOrder("KNOW_ETH").Sell().Size(size).RecvWindow(3 * time.Second).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, OrderTypeMarket, market.Type)
+	assert.Equal(t, OrderSideSell, market.OrderSide)
+	assert.Equal(t, 3000, market.RecvWindow)
+	assert.True(t, market.OrderPrice.IsZero())
+
+	stop, err := StopLimitOrder("KNOW_ETH").Sell().StopPrice(MustParseDecimal("0.000012")).Price(price).Size(size).
+		Timestamp(time.UnixMilli(1507725176599)).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "0.000012", stop.StopPrice.String())
+	assert.Equal(t, int64(1507725176599), stop.Timestamp.Millis())
+}
+
+func TestOrderBuildersRejectInvalidOrders(t *testing.T) {
+	price := MustParseDecimal("0.0000123")
+	size := NewDecimalFromInt(7777)
+
+	for name, builder := range map[string]*OrderBuilder{
+		"market with price":        MarketOrder("KNOW_ETH").Buy().Price(price).Size(size),
+		"market with stop":         MarketOrder("KNOW_ETH").Buy().StopPrice(price).Size(size),
+		"stop limit without stop":  StopLimitOrder("KNOW_ETH").Buy().Price(price).Size(size),
+		"limit with stop":          LimitOrder("KNOW_ETH").Buy().StopPrice(price).Price(price).Size(size),
+		"limit without price":      LimitOrder("KNOW_ETH").Buy().Size(size),
+		"limit without side":       LimitOrder("KNOW_ETH").Price(price).Size(size),
+		"limit without size":       LimitOrder("KNOW_ETH").Buy().Price(price),
+		"limit with negative size": LimitOrder("KNOW_ETH").Buy().Price(price).Size(size.Neg()),
+		"limit without market":     LimitOrder("").Buy().Price(price).Size(size),
+	} {
+		request, err := builder.Build()
+		assert.Nil(t, request, name)
+		assert.True(t, errors.Is(err, ErrInvalidParams), name)
+	}
+}
+
+func TestOrderBuilderWithNewOrderAndTestNewOrder(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.URL.Path == "/api/v2/order/test" {
+			w.Write([]byte(`{"result": true}`))
+			return
+		}
+		w.Write([]byte(`{"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e"}`))
+	}))
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, "key", "secret")
+	assert.Nil(t, err)
+
+	request, err := StopLimitOrder("know-eth").Sell().StopPrice(MustParseDecimal("0.000012")).
+		Price(MustParseDecimal("0.0000115")).Size(NewDecimalFromInt(100)).
+		Timestamp(time.UnixMilli(1507725176599)).RecvWindow(5 * time.Second).Build()
+	assert.Nil(t, err)
+
+	test, err := client.TestNewOrder(request)
+	assert.Nil(t, err)
+	assert.True(t, test.Result)
+	resp, err := client.NewOrder(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "02140bef-0c98-4997-9412-9e7ca6f1cc0e", resp.OrderID)
+
+	expected := `{"order_symbol":"KNOW_ETH","order_side":"SELL","order_price":"0.0000115","order_size":"100",` +
+		`"stop_price":"0.000012","type":"STOP_LIMIT","timestamp":1507725176599,"recvWindow":5000}`
+	assert.Equal(t, []string{expected, expected}, bodies)
+}
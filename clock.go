@@ -0,0 +1,149 @@
+package kryptono
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRecvWindow is the recvWindow filled into signed requests that don't set one.
+const DefaultRecvWindow = 5 * time.Second
+
+// signedRequest is implemented by all requests carrying a timestamp and recvWindow.
+type signedRequest interface {
+	// withTimestamp returns a copy of the request with timestamp and recvWindow, both in
+	// milliseconds, filled in where they are unset, and its market in "BASE_QUOTE" format.
+	withTimestamp(timestamp int64, recvWindow int64) interface{}
+	// hasTimestamp tells whether the caller set the timestamp.
+	hasTimestamp() bool
+}
+
+// WithRecvWindow sets the recvWindow filled into signed requests that don't set one. It defaults to DefaultRecvWindow.
+func WithRecvWindow(recvWindow time.Duration) ClientOption {
+	return func(c *client) error {
+		if recvWindow < time.Millisecond {
+			return fmt.Errorf("recvWindow must be at least 1ms, got %v", recvWindow)
+		}
+		c.recvWindow = recvWindow
+		return nil
+	}
+}
+
+// WithTimeSync makes the client measure the offset of the local clock to the server's clock every
+// interval using ServerTime, and correct the timestamps it fills into signed requests by it.
+func WithTimeSync(interval time.Duration) ClientOption {
+	return func(c *client) error {
+		if interval <= 0 {
+			return fmt.Errorf("time sync interval must be positive, got %v", interval)
+		}
+		c.clock.interval = interval
+		return nil
+	}
+}
+
+// serverClock estimates the server's time from the local clock and a measured offset.
+type serverClock struct {
+	mu       sync.Mutex
+	offset   time.Duration
+	synced   time.Time
+	interval time.Duration
+	now      func() time.Time
+}
+
+// serverNow returns the estimated server time, syncing the clock first if periodic sync is enabled and due.
+// If syncing fails the last known offset is used.
+func (c *client) serverNow(ctx context.Context) time.Time {
+	c.clock.mu.Lock()
+	due := c.clock.interval > 0 && c.clock.localNow().Sub(c.clock.synced) >= c.clock.interval
+	c.clock.mu.Unlock()
+	if due {
+		c.syncClock(ctx)
+	}
+
+	c.clock.mu.Lock()
+	defer c.clock.mu.Unlock()
+	return c.clock.localNow().Add(c.clock.offset)
+}
+
+// syncClock measures the offset to the server's clock, assuming the server read its clock
+// half way through the round trip.
+func (c *client) syncClock(ctx context.Context) error {
+	start := c.clock.localNow()
+	resp, err := c.ServerTimeContext(ctx)
+	if err != nil {
+		return err
+	}
+	end := c.clock.localNow()
+
+	serverTime := resp.ServerTime.Time()
+	localTime := start.Add(end.Sub(start) / 2)
+
+	c.clock.mu.Lock()
+	defer c.clock.mu.Unlock()
+	c.clock.offset = serverTime.Sub(localTime)
+	c.clock.synced = end
+	return nil
+}
+
+// clockOffset returns the size of the measured offset to the server's clock.
+func (c *client) clockOffset() time.Duration {
+	c.clock.mu.Lock()
+	defer c.clock.mu.Unlock()
+	if c.clock.offset < 0 {
+		return -c.clock.offset
+	}
+	return c.clock.offset
+}
+
+func (clock *serverClock) localNow() time.Time {
+	if clock.now != nil {
+		return clock.now()
+	}
+	return time.Now()
+}
+
+// sendSigned fills in the request's timestamp and recvWindow if unset and sends it. Responses
+// with a status other than 200 are returned as error. If the exchange rejects a filled in
+// timestamp, the clock is synced and the request is sent once more.
+func (c *client) sendSigned(ctx context.Context, request signedRequest, send func(payload interface{}) (*response, error)) (*response, error) {
+	for attempt := 1; ; attempt++ {
+		now := c.serverNow(ctx)
+		payload := request.withTimestamp(now.UnixNano()/int64(time.Millisecond), int64(c.recvWindow/time.Millisecond))
+		resp, err := send(payload)
+		// no response is left when the outcome was confirmed by other means, see sendUnconfirmed
+		if err != nil || resp == nil {
+			return nil, err
+		}
+		err = checkHTTPStatus(*resp, http.StatusOK)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt > 1 || request.hasTimestamp() || !errors.Is(err, ErrInvalidTimestamp) {
+			return nil, err
+		}
+		if syncErr := c.syncClock(ctx); syncErr != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendJSON sends payload as JSON body.
+func (c *client) sendJSON(ctx context.Context, ep endpoint, method string, url string, payload interface{}) (*response, error) {
+	asJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	switch method {
+	case http.MethodGet:
+		return c.sendGet(ctx, ep, url, nil, bytes.NewReader(asJSON))
+	case http.MethodDelete:
+		return c.sendDelete(ctx, ep, url, nil, bytes.NewReader(asJSON))
+	default:
+		return c.sendPost(ctx, ep, url, nil, bytes.NewReader(asJSON))
+	}
+}
@@ -0,0 +1,146 @@
+package kryptono
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func TestSignedRequestTimestampFilledIn(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]int64
+		reqBody, _ := ioutil.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(reqBody, &body))
+		assert.InDelta(t, millis(time.Now()), body["timestamp"], 1000)
+		assert.Equal(t, int64(2000), body["recvWindow"])
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRecvWindow(2*time.Second))
+	assert.Nil(t, err)
+
+	request := &AccountBalancesRequest{}
+	_, err = client.AccountBalances(request)
+	assert.Nil(t, err)
+	// the caller's request stays untouched
+	assert.True(t, request.Timestamp.IsZero())
+	assert.Equal(t, 0, request.RecvWindow)
+}
+
+func TestSignedRequestUsesSyncedClock(t *testing.T) {
+	var timeCalls int32
+	serverOffset := time.Hour
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/time" {
+			atomic.AddInt32(&timeCalls, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(`{"server_time": %d}`, millis(time.Now().Add(serverOffset)))))
+			return
+		}
+		var body map[string]int64
+		reqBody, _ := ioutil.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(reqBody, &body))
+		assert.InDelta(t, millis(time.Now().Add(serverOffset)), body["timestamp"], 1000)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithAccountAPIEndpoint(ts.URL), WithTimeSync(time.Minute))
+	assert.Nil(t, err)
+
+	_, err = client.AccountBalances(&AccountBalancesRequest{})
+	assert.Nil(t, err)
+	_, err = client.AccountBalances(&AccountBalancesRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&timeCalls))
+}
+
+func TestSyncClockCorrectsForRoundTrip(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf(`{"server_time": %d}`, millis(time.Date(2019, 11, 24, 11, 0, 10, 500000000, time.UTC)))))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL))
+	assert.Nil(t, err)
+	impl := c.(*client)
+	// the round trip takes a second, the server is assumed to answer after half of it
+	local := time.Date(2019, 11, 24, 11, 0, 0, 0, time.UTC)
+	impl.clock.now = func() time.Time {
+		now := local
+		local = local.Add(time.Second)
+		return now
+	}
+
+	assert.Nil(t, impl.syncClock(context.Background()))
+	assert.Equal(t, 10*time.Second, impl.clock.offset)
+}
+
+func TestSignedRequestResyncsOnTimestampRejection(t *testing.T) {
+	var timeCalls, orderCalls int32
+	serverOffset := -time.Hour
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/time" {
+			atomic.AddInt32(&timeCalls, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(`{"server_time": %d}`, millis(time.Now().Add(serverOffset)))))
+			return
+		}
+		atomic.AddInt32(&orderCalls, 1)
+		var body map[string]interface{}
+		reqBody, _ := ioutil.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(reqBody, &body))
+		skew := time.Duration(millis(time.Now().Add(serverOffset))-int64(body["timestamp"].(float64))) * time.Millisecond
+		if skew > 5*time.Second || skew < -5*time.Second {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "INVALID_TIMESTAMP", "error_description": "Timestamp for this request is outside of the recvWindow"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e"}`))
+	}))
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, "key", "secret")
+	assert.Nil(t, err)
+
+	request := testNewOrderRequest()
+	request.Timestamp = Timestamp{}
+	resp, err := client.NewOrder(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "02140bef-0c98-4997-9412-9e7ca6f1cc0e", resp.OrderID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&timeCalls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&orderCalls))
+
+	// a timestamp set by the caller is sent as is
+	_, err = client.NewOrder(testNewOrderRequest())
+	assert.True(t, errors.Is(err, ErrInvalidTimestamp), err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&timeCalls))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&orderCalls))
+}
+
+func TestTimeOptionsValidation(t *testing.T) {
+	_, err := NewClient("key", "secret", WithRecvWindow(0))
+	assert.NotNil(t, err)
+	_, err = NewClient("key", "secret", WithTimeSync(0))
+	assert.NotNil(t, err)
+}
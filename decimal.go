@@ -0,0 +1,409 @@
+package kryptono
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, used for all prices, quantities and fees. It keeps the
+// number of decimal places it was created with, so "0.00001230" round-trips unchanged. The zero
+// value is 0. Decimals are immutable, arithmetic returns new values.
+//
+// Decimals are encoded as JSON strings, except for values decoded from a JSON number, which
+// are encoded as numbers again.
+type Decimal struct {
+	// value is coef * 10^-scale, a nil coef is 0
+	coef   *big.Int
+	scale  int32
+	number bool
+}
+
+// RoundingMode is how a value is rounded to fewer decimal places.
+type RoundingMode int
+
+const (
+	// RoundDown rounds toward zero.
+	RoundDown RoundingMode = iota
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundHalfEven rounds to the nearest value, and to the even one if both are equally near.
+	RoundHalfEven
+	// RoundTowardTouch rounds a price toward the other side of the book, up for buys and down
+	// for sells, making the order more likely to fill.
+	RoundTowardTouch
+	// RoundAwayFromTouch rounds a price away from the other side of the book, down for buys and
+	// up for sells, making the order more passive.
+	RoundAwayFromTouch
+)
+
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	case RoundHalfEven:
+		return "half_even"
+	case RoundTowardTouch:
+		return "toward_touch"
+	case RoundAwayFromTouch:
+		return "away_from_touch"
+	default:
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+}
+
+// NewDecimal returns value * 10^-scale, e.g. NewDecimal(123, 2) is 1.23.
+func NewDecimal(value int64, scale int32) Decimal {
+	if scale < 0 {
+		coef := new(big.Int).Mul(big.NewInt(value), pow10(-scale))
+		return Decimal{coef: coef}
+	}
+	return Decimal{coef: big.NewInt(value), scale: scale}
+}
+
+// NewDecimalFromInt returns value as Decimal.
+func NewDecimalFromInt(value int64) Decimal {
+	return NewDecimal(value, 0)
+}
+
+// NewDecimalFromFloat returns the shortest decimal representation of value.
+func NewDecimalFromFloat(value float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(value, 'f', -1, 64))
+	if err != nil {
+		// NaN and infinities can't be represented
+		return Decimal{}
+	}
+	return d
+}
+
+// largest exponent and number of decimal places ParseDecimal accepts
+const maxDecimalExponent = 1000
+
+// ParseDecimal parses a decimal number like "-0.001230", "42" or "4e-8". Exponents and decimal
+// places beyond 1000 are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		exp, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		if exp > maxDecimalExponent || exp < -maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("decimal %q is out of range", s)
+		}
+	}
+
+	digits := mantissa
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	var scale int64
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		scale = int64(len(digits) - i - 1)
+		digits = digits[:i] + digits[i+1:]
+	}
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if strings.HasPrefix(mantissa, "-") {
+		coef.Neg(coef)
+	}
+	scale -= exp
+	// a large scale either way would take long to compute with and doesn't fit prices
+	if scale > maxDecimalExponent || scale < -maxDecimalExponent {
+		return Decimal{}, fmt.Errorf("decimal %q is out of range", s)
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s isn't a valid decimal.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// String returns d in plain notation with all its decimal places.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.bigCoef()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Scale returns the number of decimal places of d.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.bigCoef().Sign()
+}
+
+// IsZero tells whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.bigCoef()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.bigCoef()), scale: d.scale}
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{coef: a.Add(a, b), scale: scale}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{coef: a.Sub(a, b), scale: scale}
+}
+
+// Mul returns d * other, with as many decimal places as both together.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.bigCoef(), other.bigCoef()), scale: d.scale + other.scale}
+}
+
+// Div returns d / other rounded half to even to scale decimal places. It panics if other is 0.
+func (d Decimal) Div(other Decimal, scale int32) Decimal {
+	return d.DivRound(other, scale, RoundHalfEven)
+}
+
+// DivRound returns d / other rounded with mode to scale decimal places. It panics if other is 0
+// or mode is one of the touch modes.
+func (d Decimal) DivRound(other Decimal, scale int32, mode RoundingMode) Decimal {
+	if other.IsZero() {
+		panic("kryptono: division of decimal by zero")
+	}
+	// d / other = (d.coef * 10^other.scale) / (other.coef * 10^d.scale)
+	num := new(big.Int).Mul(d.bigCoef(), pow10(other.scale))
+	den := new(big.Int).Mul(other.bigCoef(), pow10(d.scale))
+	return quo(num, den, scale, mode)
+}
+
+// Round returns d rounded with mode to places decimal places. Values with fewer decimal places
+// are returned unchanged. It panics if mode is one of the touch modes, they need the side of an
+// order, see Quantizer.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	return quo(d.bigCoef(), pow10(d.scale), places, mode)
+}
+
+// Cmp returns -1 if d < other, 0 if d == other and +1 if d > other.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal tells whether d and other have the same value, regardless of their decimal places.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// LessThan tells whether d < other.
+func (d Decimal) LessThan(other Decimal) bool {
+	return d.Cmp(other) < 0
+}
+
+// GreaterThan tells whether d > other.
+func (d Decimal) GreaterThan(other Decimal) bool {
+	return d.Cmp(other) > 0
+}
+
+// MarshalJSON encodes d as JSON string, or as JSON number if it was decoded from one.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.number {
+		return []byte(d.String()), nil
+	}
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decodes a JSON string or number. null and "" decode to 0.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+
+	number := true
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		number = false
+		if strings.TrimSpace(s) == "" {
+			*d = Decimal{}
+			return nil
+		}
+	}
+	parsed, err := ParseDecimal(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	parsed.number = number
+	*d = parsed
+	return nil
+}
+
+func (d Decimal) bigCoef() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// align returns copies of the coefficients of a and b at their common scale.
+func align(a Decimal, b Decimal) (*big.Int, *big.Int, int32) {
+	ac, bc := new(big.Int).Set(a.bigCoef()), new(big.Int).Set(b.bigCoef())
+	switch {
+	case a.scale < b.scale:
+		ac.Mul(ac, pow10(b.scale-a.scale))
+		return ac, bc, b.scale
+	case a.scale > b.scale:
+		bc.Mul(bc, pow10(a.scale-b.scale))
+		return ac, bc, a.scale
+	default:
+		return ac, bc, a.scale
+	}
+}
+
+// quo returns num / den rounded with mode to scale decimal places.
+func quo(num *big.Int, den *big.Int, scale int32, mode RoundingMode) Decimal {
+	if scale >= 0 {
+		num = new(big.Int).Mul(num, pow10(scale))
+	} else {
+		den = new(big.Int).Mul(den, pow10(-scale))
+	}
+	if den.Sign() < 0 {
+		num, den = new(big.Int).Neg(num), new(big.Int).Neg(den)
+	}
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	var away bool
+	switch mode {
+	case RoundDown:
+	case RoundUp:
+		away = r.Sign() != 0
+	case RoundHalfEven:
+		// compare the remainder to half of the divisor
+		half := new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(den)
+		away = half > 0 || (half == 0 && q.Bit(0) == 1)
+	default:
+		panic(fmt.Sprintf("kryptono: can't round decimal with mode %s", mode))
+	}
+	if away {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	if scale < 0 {
+		return Decimal{coef: q.Mul(q, pow10(-scale))}
+	}
+	return Decimal{coef: q, scale: scale}
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// NullDecimal is a Decimal that may be missing. JSON null and "" decode to an invalid NullDecimal,
+// which encodes as null.
+type NullDecimal struct {
+	Decimal Decimal
+	// Valid is false if the value is missing
+	Valid bool
+}
+
+// NewNullDecimal returns d as valid NullDecimal.
+func NewNullDecimal(d Decimal) NullDecimal {
+	return NullDecimal{Decimal: d, Valid: true}
+}
+
+// MarshalJSON encodes the decimal, or null if it is invalid.
+func (n NullDecimal) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Decimal.MarshalJSON()
+}
+
+// UnmarshalJSON decodes a JSON string or number, null and "" decode to an invalid NullDecimal.
+func (n *NullDecimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte(`""`)) {
+		*n = NullDecimal{}
+		return nil
+	}
+	var d Decimal
+	if err := d.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*n = NullDecimal{Decimal: d, Valid: true}
+	return nil
+}
+
+// places returns the number of decimal places d needs, without trailing zeros.
+func (d Decimal) places() int32 {
+	if d.IsZero() || d.scale == 0 {
+		return 0
+	}
+	coef := new(big.Int).Abs(d.coef)
+	ten := big.NewInt(10)
+	remainder := new(big.Int)
+	scale := d.scale
+	for scale > 0 {
+		q, r := new(big.Int).QuoRem(coef, ten, remainder)
+		if r.Sign() != 0 {
+			break
+		}
+		coef = q
+		scale--
+	}
+	return scale
+}
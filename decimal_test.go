@@ -0,0 +1,140 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for input, expected := range map[string]string{
+		"0":           "0",
+		"42":          "42",
+		"-0.001230":   "-0.001230",
+		"+1.5":        "1.5",
+		".5":          "0.5",
+		"17790.00000": "17790.00000",
+		"4e-8":        "0.00000004",
+		"1.5E3":       "1500",
+		"-0":          "0",
+	} {
+		d, err := ParseDecimal(input)
+		assert.Nil(t, err, input)
+		assert.Equal(t, expected, d.String(), input)
+	}
+
+	for _, input := range []string{"", "-", ".", "1.2.3", "abc", "1e", "0x10", "1,5"} {
+		_, err := ParseDecimal(input)
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestParseDecimalRange(t *testing.T) {
+	for _, input := range []string{"1e1000", "1e-1000", "0.5e-999"} {
+		_, err := ParseDecimal(input)
+		assert.Nil(t, err, input)
+	}
+	for _, input := range []string{"1e200000000", "1e-200000000", "1e1001", "0.5e-1000", "1e2147483647", "1e99999999999", "0." + strings.Repeat("1", 1001)} {
+		_, err := ParseDecimal(input)
+		assert.NotNil(t, err, input)
+	}
+
+	var d Decimal
+	assert.NotNil(t, json.Unmarshal([]byte(`"1e200000000"`), &d))
+	assert.NotNil(t, json.Unmarshal([]byte(`1e200000000`), &d))
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := MustParseDecimal("0.00001230")
+	b := MustParseDecimal("0.1")
+
+	assert.Equal(t, "0.10001230", a.Add(b).String())
+	assert.Equal(t, "-0.09998770", a.Sub(b).String())
+	assert.Equal(t, "0.000001230", a.Mul(b).String())
+	assert.Equal(t, "0.000123", a.Div(b, 6).String())
+	assert.Equal(t, "0.33333333", NewDecimalFromInt(1).Div(NewDecimalFromInt(3), 8).String())
+	assert.Equal(t, "0.67", NewDecimalFromInt(2).Div(NewDecimalFromInt(3), 2).String())
+	assert.Equal(t, "-0.67", NewDecimalFromInt(-2).Div(NewDecimalFromInt(3), 2).String())
+	// half to even
+	assert.Equal(t, "0.2", MustParseDecimal("0.25").Div(NewDecimalFromInt(1), 1).String())
+	assert.Equal(t, "0.4", MustParseDecimal("0.35").Div(NewDecimalFromInt(1), 1).String())
+	assert.Equal(t, "1.23", NewDecimal(123, 2).String())
+	assert.Equal(t, "1200", NewDecimal(12, -2).String())
+
+	assert.Equal(t, "-0.00001230", a.Neg().String())
+	assert.Equal(t, "0.00001230", a.Neg().Abs().String())
+	assert.Panics(t, func() { a.Div(Decimal{}, 2) })
+}
+
+func TestDecimalCompare(t *testing.T) {
+	assert.True(t, MustParseDecimal("1.50").Equal(MustParseDecimal("1.5")))
+	assert.Equal(t, -1, MustParseDecimal("0.9").Cmp(NewDecimalFromInt(1)))
+	assert.Equal(t, 1, MustParseDecimal("-0.9").Cmp(NewDecimalFromInt(-1)))
+	assert.True(t, MustParseDecimal("0.00000001").GreaterThan(Decimal{}))
+	assert.True(t, MustParseDecimal("-0.00000001").LessThan(Decimal{}))
+	assert.True(t, Decimal{}.IsZero())
+	assert.True(t, MustParseDecimal("0.000").IsZero())
+	assert.Equal(t, -1, MustParseDecimal("-3").Sign())
+	assert.Equal(t, 0.0000123, MustParseDecimal("0.00001230").Float64())
+	assert.Equal(t, "0.1", NewDecimalFromFloat(0.1).String())
+	assert.Equal(t, int32(8), MustParseDecimal("0.00001230").Scale())
+	assert.Equal(t, int32(7), MustParseDecimal("0.00001230").places())
+	assert.Equal(t, int32(0), MustParseDecimal("1500.000").places())
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var value struct {
+		Str    Decimal `json:"str"`
+		Number Decimal `json:"number"`
+		Null   Decimal `json:"null"`
+		Empty  Decimal `json:"empty"`
+	}
+	input := `{"str":"0.00001230","number":4e-8,"null":null,"empty":""}`
+	assert.Nil(t, json.Unmarshal([]byte(input), &value))
+	assert.Equal(t, "0.00001230", value.Str.String())
+	assert.Equal(t, "0.00000004", value.Number.String())
+	assert.True(t, value.Null.IsZero())
+	assert.True(t, value.Empty.IsZero())
+
+	output, err := json.Marshal(value)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"str":"0.00001230","number":0.00000004,"null":"0","empty":"0"}`, string(output))
+
+	// results of arithmetic are strings
+	output, err = json.Marshal(value.Number.Add(value.Str))
+	assert.Nil(t, err)
+	assert.Equal(t, `"0.00001234"`, string(output))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"1.2.3"`), &value.Str))
+	assert.NotNil(t, json.Unmarshal([]byte(`true`), &value.Str))
+}
+
+func TestDecimalRound(t *testing.T) {
+	for _, c := range []struct {
+		value    string
+		places   int32
+		mode     RoundingMode
+		expected string
+	}{
+		{"0.000012345", 8, RoundDown, "0.00001234"},
+		{"0.000012345", 8, RoundUp, "0.00001235"},
+		{"0.000012345", 8, RoundHalfEven, "0.00001234"},
+		{"0.000012355", 8, RoundHalfEven, "0.00001236"},
+		{"-1.25", 1, RoundDown, "-1.2"},
+		{"-1.25", 1, RoundUp, "-1.3"},
+		{"-1.25", 1, RoundHalfEven, "-1.2"},
+		{"1.20", 0, RoundUp, "2"},
+		{"1.00", 0, RoundUp, "1"},
+		{"0.1", 8, RoundUp, "0.1"},
+		{"1234.5", -2, RoundDown, "1200"},
+	} {
+		assert.Equal(t, c.expected, MustParseDecimal(c.value).Round(c.places, c.mode).String(), c)
+	}
+
+	assert.Equal(t, "0.34", NewDecimalFromInt(1).DivRound(NewDecimalFromInt(3), 2, RoundUp).String())
+	assert.Equal(t, "0.33", NewDecimalFromInt(1).DivRound(NewDecimalFromInt(3), 2, RoundHalfEven).String())
+	assert.Panics(t, func() { MustParseDecimal("1.25").Round(1, RoundTowardTouch) })
+	assert.Equal(t, "away_from_touch", RoundAwayFromTouch.String())
+}
@@ -0,0 +1,34 @@
+package kryptono
+
+// endpoint describes a single API call of the client.
+type endpoint struct {
+	name string
+	// idempotent endpoints only read state and can be retried freely
+	idempotent bool
+	// weight is what a call costs of a REQUESTS rate limit
+	weight int
+	// placesOrder calls count against ORDERS rate limits
+	placesOrder bool
+}
+
+// The exchange doesn't document weights, endpoints returning lists are assumed to be more expensive.
+var (
+	endpointPing                = endpoint{name: "Ping", idempotent: true, weight: 1}
+	endpointServerTime          = endpoint{name: "ServerTime", idempotent: true, weight: 1}
+	endpointExchangeInformation = endpoint{name: "ExchangeInformation", idempotent: true, weight: 5}
+	endpointMarketPrice         = endpoint{name: "MarketPrice", idempotent: true, weight: 1}
+	endpointTradeHistory        = endpoint{name: "TradeHistory", idempotent: true, weight: 5}
+	endpointOrderBook           = endpoint{name: "OrderBook", idempotent: true, weight: 5}
+	endpointMarketSummaries     = endpoint{name: "MarketSummaries", idempotent: true, weight: 5}
+	endpointNewOrder            = endpoint{name: "NewOrder", weight: 1, placesOrder: true}
+	endpointTestNewOrder        = endpoint{name: "TestNewOrder", idempotent: true, weight: 1} // validates but never places an order
+	endpointOrderDetail         = endpoint{name: "OrderDetail", idempotent: true, weight: 1}
+	endpointCancelOrder         = endpoint{name: "CancelOrder", weight: 1}
+	endpointTradeDetails        = endpoint{name: "TradeDetails", idempotent: true, weight: 1}
+	endpointOpenOrders          = endpoint{name: "OpenOrders", idempotent: true, weight: 5}
+	endpointCompletedOrders     = endpoint{name: "CompletedOrders", idempotent: true, weight: 5}
+	endpointAllOrders           = endpoint{name: "AllOrders", idempotent: true, weight: 5}
+	endpointTradeList           = endpoint{name: "TradeList", idempotent: true, weight: 5}
+	endpointAccountInformation  = endpoint{name: "AccountInformation", idempotent: true, weight: 1}
+	endpointAccountBalances     = endpoint{name: "AccountBalances", idempotent: true, weight: 1}
+)
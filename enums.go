@@ -0,0 +1,148 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// OrderSide is the side of an order.
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+	// OrderSideUnknown is decoded for sides this package doesn't know.
+	OrderSideUnknown OrderSide = "UNKNOWN"
+)
+
+var orderSides = []OrderSide{OrderSideBuy, OrderSideSell}
+
+// IsValid tells whether s is a known order side, regardless of its case.
+func (s OrderSide) IsValid() bool {
+	return canonical(string(s), orderSides) != ""
+}
+
+// MarshalJSON encodes known sides in their canonical spelling.
+func (s OrderSide) MarshalJSON() ([]byte, error) {
+	if c := canonical(string(s), orderSides); c != "" {
+		s = c
+	}
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON decodes the side case-insensitively, unknown sides decode to OrderSideUnknown.
+func (s *OrderSide) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, orderSides, OrderSideUnknown)
+}
+
+// OrderType is the type of an order.
+type OrderType string
+
+const (
+	OrderTypeLimit     OrderType = "LIMIT"
+	OrderTypeMarket    OrderType = "MARKET"
+	OrderTypeStopLimit OrderType = "STOP_LIMIT"
+	// OrderTypeUnknown is decoded for types this package doesn't know.
+	OrderTypeUnknown OrderType = "UNKNOWN"
+)
+
+var orderTypes = []OrderType{OrderTypeLimit, OrderTypeMarket, OrderTypeStopLimit}
+
+// IsValid tells whether t is a known order type, regardless of its case.
+func (t OrderType) IsValid() bool {
+	return canonical(string(t), orderTypes) != ""
+}
+
+// MarshalJSON encodes known types in their canonical spelling.
+func (t OrderType) MarshalJSON() ([]byte, error) {
+	if c := canonical(string(t), orderTypes); c != "" {
+		t = c
+	}
+	return json.Marshal(string(t))
+}
+
+// UnmarshalJSON decodes the type case-insensitively, unknown types decode to OrderTypeUnknown.
+func (t *OrderType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, t, orderTypes, OrderTypeUnknown)
+}
+
+// OrderStatus is the status of an order.
+type OrderStatus string
+
+const (
+	OrderStatusOpen            OrderStatus = "open"
+	OrderStatusPartiallyFilled OrderStatus = "partially_filled"
+	OrderStatusFilled          OrderStatus = "filled"
+	OrderStatusCanceled        OrderStatus = "canceled"
+	// OrderStatusUnknown is decoded for statuses this package doesn't know.
+	OrderStatusUnknown OrderStatus = "unknown"
+)
+
+var orderStatuses = []OrderStatus{OrderStatusOpen, OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCanceled}
+
+// IsValid tells whether s is a known order status, regardless of its case.
+func (s OrderStatus) IsValid() bool {
+	return canonicalStatus(string(s)) != ""
+}
+
+// MarshalJSON encodes known statuses in their canonical spelling.
+func (s OrderStatus) MarshalJSON() ([]byte, error) {
+	if c := canonicalStatus(string(s)); c != "" {
+		s = c
+	}
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON decodes the status case-insensitively, unknown statuses decode to OrderStatusUnknown.
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch {
+	case raw == "":
+		*s = ""
+	case canonicalStatus(raw) != "":
+		*s = canonicalStatus(raw)
+	default:
+		*s = OrderStatusUnknown
+	}
+	return nil
+}
+
+func canonicalStatus(s string) OrderStatus {
+	// the exchange isn't consistent about the spelling
+	switch strings.ToLower(s) {
+	case "cancelled":
+		return OrderStatusCanceled
+	case "partially filled", "partiallyfilled":
+		return OrderStatusPartiallyFilled
+	}
+	return canonical(s, orderStatuses)
+}
+
+// canonical returns the value of known matching s case-insensitively, or "".
+func canonical[T ~string](s string, known []T) T {
+	for _, k := range known {
+		if strings.EqualFold(s, string(k)) {
+			return k
+		}
+	}
+	return ""
+}
+
+func unmarshalEnum[T ~string](data []byte, value *T, known []T, unknown T) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch c := canonical(raw, known); {
+	case raw == "":
+		*value = ""
+	case c != "":
+		*value = c
+	default:
+		*value = unknown
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderEnumsUnmarshal(t *testing.T) {
+	var resp NewOrderResp
+	err := json.Unmarshal([]byte(`{"order_side": "buy", "type": "limit", "status": "Partially_Filled"}`), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderSideBuy, resp.OrderSide)
+	assert.Equal(t, OrderTypeLimit, resp.Type)
+	assert.Equal(t, OrderStatusPartiallyFilled, resp.Status)
+
+	err = json.Unmarshal([]byte(`{"order_side": "SHORT", "type": "ICEBERG", "status": "expired"}`), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderSideUnknown, resp.OrderSide)
+	assert.Equal(t, OrderTypeUnknown, resp.Type)
+	assert.Equal(t, OrderStatusUnknown, resp.Status)
+
+	err = json.Unmarshal([]byte(`{"status": "CANCELLED"}`), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderStatusCanceled, resp.Status)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"status": 1}`), &resp))
+}
+
+func TestOrderEnumsMarshal(t *testing.T) {
+	output, err := json.Marshal(struct {
+		Side   OrderSide   `json:"side"`
+		Type   OrderType   `json:"type"`
+		Status OrderStatus `json:"status"`
+	}{"sell", "stop_limit", "FILLED"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"side":"SELL","type":"STOP_LIMIT","status":"filled"}`, string(output))
+}
+
+func TestNewOrderRequestValidate(t *testing.T) {
+	stop := MustParseDecimal("0.00001")
+	valid := []NewOrderRequest{
+		{OrderSymbol: "KNOW_BTC", OrderSide: OrderSideBuy, Type: OrderTypeLimit, OrderPrice: MustParseDecimal("0.00002"), OrderSize: NewDecimalFromInt(10)},
+		{OrderSymbol: "KNOW_BTC", OrderSide: "sell", Type: "market", OrderSize: NewDecimalFromInt(10)},
+		{OrderSymbol: "KNOW_BTC", OrderSide: OrderSideSell, Type: OrderTypeStopLimit, OrderPrice: MustParseDecimal("0.00002"), OrderSize: NewDecimalFromInt(10), StopPrice: &stop},
+	}
+	for _, r := range valid {
+		assert.Nil(t, r.Validate(), r)
+	}
+
+	invalid := []NewOrderRequest{
+		{OrderSide: OrderSideBuy, Type: OrderTypeLimit, OrderPrice: MustParseDecimal("0.00002"), OrderSize: NewDecimalFromInt(10)},
+		{OrderSymbol: "KNOW_BTC", OrderSide: "LONG", Type: OrderTypeLimit, OrderPrice: MustParseDecimal("0.00002"), OrderSize: NewDecimalFromInt(10)},
+		{OrderSymbol: "KNOW_BTC", OrderSide: OrderSideBuy, Type: OrderTypeUnknown, OrderPrice: MustParseDecimal("0.00002"), OrderSize: NewDecimalFromInt(10)},
+		{OrderSymbol: "KNOW_BTC", OrderSide: OrderSideBuy, Type: OrderTypeLimit, OrderPrice: MustParseDecimal("0.00002")},
+		{OrderSymbol: "KNOW_BTC", OrderSide: OrderSideBuy, Type: OrderTypeLimit, OrderSize: NewDecimalFromInt(10)},
+		{OrderSymbol: "KNOW_BTC", OrderSide: OrderSideBuy, Type: OrderTypeStopLimit, OrderPrice: MustParseDecimal("0.00002"), OrderSize: NewDecimalFromInt(10)},
+		{OrderSymbol: "KNOW_BTC", OrderSide: OrderSideBuy, Type: OrderTypeLimit, OrderPrice: MustParseDecimal("0.00002"), OrderSize: NewDecimalFromInt(10), StopPrice: &stop},
+	}
+	for _, r := range invalid {
+		err := r.Validate()
+		assert.True(t, errors.Is(err, ErrInvalidParams), r)
+	}
+}
+
+func TestNewOrderInvalidIsNotSent(t *testing.T) {
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint("http://127.0.0.1:1"))
+	assert.Nil(t, err)
+
+	_, err = client.NewOrder(&NewOrderRequest{OrderSymbol: "KNOW_BTC", OrderSide: OrderSideBuy, Type: OrderTypeLimit})
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+}
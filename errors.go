@@ -0,0 +1,152 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Categories of errors returned by the exchange. Use errors.Is to check an error against them.
+var (
+	// ErrUnauthorized: the API key is unknown or lacks permissions, or the signature is wrong.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited: too many requests.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrInvalidParams: the request was rejected because of missing or malformed parameters.
+	ErrInvalidParams = errors.New("invalid parameters")
+	// ErrInvalidTimestamp: the request's timestamp is outside of the server's recvWindow.
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	// ErrInsufficientFunds: the account's balance doesn't cover the order.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrNotFound: the order or resource doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrServer: the exchange failed to handle the request.
+	ErrServer = errors.New("server error")
+)
+
+// maximum number of bytes of an error response kept in APIError.Body
+const maxErrorBodySize = 64 * 1024
+
+// APIError is returned when the exchange answers with an unexpected HTTP status.
+type APIError struct {
+	// StatusCode and Status of the HTTP response.
+	StatusCode int
+	Status     string
+	// Endpoint is the client method that failed, e.g. "NewOrder".
+	Endpoint string
+	Method   string
+	URL      string
+	// Body is the raw response body.
+	Body []byte
+	// Code and Message are the exchange's error code and message, if the body contained them.
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s (%s) failed with http status %d", e.Method, e.URL, e.Endpoint, e.StatusCode)
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s, code %s", msg, e.Code)
+	}
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	return msg
+}
+
+// Is matches the error's category, see ErrUnauthorized, ErrRateLimited and the other category errors.
+func (e *APIError) Is(target error) bool {
+	return target != nil && e.Category() == target
+}
+
+// Category returns the category error matching e, or nil if e falls in none of them.
+func (e *APIError) Category() error {
+	text := strings.ToLower(e.Code + " " + e.Message)
+	contains := func(words ...string) bool {
+		for _, w := range words {
+			if strings.Contains(text, w) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests || contains("rate limit", "too many"):
+		return ErrRateLimited
+	case contains("timestamp", "recvwindow"):
+		return ErrInvalidTimestamp
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || contains("signature", "api key", "apikey", "unauthori"):
+		return ErrUnauthorized
+	case contains("insufficient", "balance"):
+		return ErrInsufficientFunds
+	case e.StatusCode == http.StatusNotFound || contains("not found", "not_found", "not exist"):
+		return ErrNotFound
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	case e.StatusCode >= http.StatusBadRequest:
+		return ErrInvalidParams
+	default:
+		return nil
+	}
+}
+
+// newAPIError reads up to maxErrorBodySize bytes of the response body and parses the exchange's error code and message.
+func newAPIError(resp response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Endpoint:   resp.Endpoint,
+		Method:     resp.Method,
+		URL:        resp.URL,
+	}
+	if resp.Body != nil {
+		e.Body, _ = ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		resp.Body.Close()
+	}
+	e.Code, e.Message = parseErrorBody(e.Body)
+	return e
+}
+
+// parseErrorBody looks for the error code and message in the fields used by the exchange.
+func parseErrorBody(body []byte) (code string, message string) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", strings.TrimSpace(string(body))
+	}
+
+	for _, key := range []string{"code", "error_code", "error"} {
+		if code = jsonScalar(fields[key]); code != "" {
+			break
+		}
+	}
+	for _, key := range []string{"message", "msg", "error_description", "error_message"} {
+		if message = jsonScalar(fields[key]); message != "" {
+			break
+		}
+	}
+	return code, message
+}
+
+// jsonScalar returns a JSON string or number as string.
+func jsonScalar(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		if _, err := strconv.ParseFloat(n.String(), 64); err == nil {
+			return n.String()
+		}
+	}
+	return ""
+}
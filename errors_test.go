@@ -0,0 +1,85 @@
+package kryptono
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorCategories(t *testing.T) {
+	tests := []struct {
+		status   int
+		body     string
+		category error
+	}{
+		{http.StatusUnauthorized, `{"error": "UNAUTHORIZED"}`, ErrUnauthorized},
+		{http.StatusBadRequest, `{"error": "INVALID_SIGNATURE", "error_description": "Signature is invalid"}`, ErrUnauthorized},
+		{http.StatusTooManyRequests, ``, ErrRateLimited},
+		{http.StatusBadRequest, `{"code": 1021, "message": "Timestamp for this request is outside of the recvWindow"}`, ErrInvalidTimestamp},
+		{http.StatusBadRequest, `{"error": "INSUFFICIENT_BALANCE", "error_description": "Insufficient balance"}`, ErrInsufficientFunds},
+		{http.StatusBadRequest, `{"error": "ORDER_NOT_FOUND"}`, ErrNotFound},
+		{http.StatusNotFound, `not here`, ErrNotFound},
+		{http.StatusBadRequest, `{"error": "INVALID_PARAMETER", "error_description": "order_size is required"}`, ErrInvalidParams},
+		{http.StatusBadGateway, `<html>bad gateway</html>`, ErrServer},
+	}
+
+	for _, test := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.body))
+		}))
+
+		client, err := newClientWithURL(ts.URL, "key", "secret")
+		assert.Nil(t, err)
+		_, err = client.Ping()
+		ts.Close()
+
+		assert.True(t, errors.Is(err, test.category), "%s should be %v, got %v", test.body, test.category, err)
+
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, test.status, apiErr.StatusCode)
+		assert.Equal(t, "Ping", apiErr.Endpoint)
+		assert.Equal(t, "GET", apiErr.Method)
+		assert.Equal(t, ts.URL+"/api/v2/ping", apiErr.URL)
+		assert.Equal(t, test.body, string(apiErr.Body))
+	}
+}
+
+func TestAPIErrorParsesCodeAndMessage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "INSUFFICIENT_BALANCE", "error_description": "Insufficient balance"}`))
+	}))
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, "key", "secret")
+	assert.Nil(t, err)
+	_, err = client.NewOrder(testNewOrderRequest())
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "NewOrder", apiErr.Endpoint)
+	assert.Equal(t, "POST", apiErr.Method)
+	assert.Equal(t, "INSUFFICIENT_BALANCE", apiErr.Code)
+	assert.Equal(t, "Insufficient balance", apiErr.Message)
+	assert.False(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, "POST "+ts.URL+"/api/v2/order/add (NewOrder) failed with http status 400, code INSUFFICIENT_BALANCE: Insufficient balance", err.Error())
+}
+
+func TestParseErrorBody(t *testing.T) {
+	code, message := parseErrorBody([]byte(`{"code": -1013, "msg": "Filter failure: LOT_SIZE"}`))
+	assert.Equal(t, "-1013", code)
+	assert.Equal(t, "Filter failure: LOT_SIZE", message)
+
+	code, message = parseErrorBody([]byte(` something went wrong `))
+	assert.Equal(t, "", code)
+	assert.Equal(t, "something went wrong", message)
+}
+
+func TestRateLimitExceededIsRateLimited(t *testing.T) {
+	assert.True(t, errors.Is(ErrRateLimitExceeded, ErrRateLimited))
+}
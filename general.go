@@ -1,10 +1,8 @@
 package kryptono
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 )
 
 type PingResp struct {
@@ -12,12 +10,12 @@ type PingResp struct {
 }
 
 type ServerTimeResp struct {
-	ServerTime int `json:"server_time"`
+	ServerTime Timestamp `json:"server_time"`
 }
 
 type ExchangeInformationResp struct {
 	Timezone       string         `json:"timezone"`
-	ServerTime     int            `json:"server_time"`
+	ServerTime     Timestamp      `json:"server_time"`
 	RateLimits     []RateLimit    `json:"rate_limits"`
 	BaseCurrencies []BaseCurrency `json:"base_currencies"`
 	Coins          []Coin         `json:"coins"`
@@ -26,13 +24,13 @@ type ExchangeInformationResp struct {
 
 type BaseCurrency struct {
 	CurrencyCode      string  `json:"currency_code"`
-	MinimumTotalOrder float64 `json:"minimum_total_order,string"`
+	MinimumTotalOrder Decimal `json:"minimum_total_order"`
 }
 
 type Coin struct {
 	CurrencyCode       string  `json:"currency_code"`
 	Name               string  `json:"name"`
-	MinimumOrderAmount float64 `json:"minimum_order_amount,string"`
+	MinimumOrderAmount Decimal `json:"minimum_order_amount"`
 }
 
 type RateLimit struct {
@@ -42,7 +40,7 @@ type RateLimit struct {
 }
 
 type Symbol struct {
-	Symbol             string  `json:"symbol"`
+	Symbol             Market  `json:"symbol"`
 	AmountLimitDecimal float64 `json:"amount_limit_decimal"`
 	PriceLimitDecimal  float64 `json:"price_limit_decimal"`
 	AllowTrading       bool    `json:"allow_trading"`
@@ -51,105 +49,64 @@ type Symbol struct {
 type MarketPriceResp []MarketPriceRespElement
 
 type MarketPriceRespElement struct {
-	Symbol      string  `json:"symbol"`
-	Price       float64 `json:"price,string"`
-	UpdatedTime int     `json:"updated_time"`
+	Symbol      Market    `json:"symbol"`
+	Price       Decimal   `json:"price"`
+	UpdatedTime Timestamp `json:"updated_time"`
 }
 
 func (c *client) Ping() (*PingResp, error) {
-	url := fmt.Sprintf("%s/api/v2/ping", c.generalAPIEndpoint)
-	resp, err := c.sendGet(url, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return c.PingContext(context.Background())
+}
 
+func (c *client) PingContext(ctx context.Context) (*PingResp, error) {
+	url := fmt.Sprintf("%s/api/v2/ping", c.generalAPIEndpoint)
 	var result PingResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.get(ctx, endpointPing, url, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 func (c *client) ServerTime() (*ServerTimeResp, error) {
-	url := fmt.Sprintf("%s/api/v2/time", c.generalAPIEndpoint)
-	resp, err := c.sendGet(url, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return c.ServerTimeContext(context.Background())
+}
 
+func (c *client) ServerTimeContext(ctx context.Context) (*ServerTimeResp, error) {
+	url := fmt.Sprintf("%s/api/v2/time", c.generalAPIEndpoint)
 	var result ServerTimeResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.get(ctx, endpointServerTime, url, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 func (c *client) ExchangeInformation() (*ExchangeInformationResp, error) {
-	url := fmt.Sprintf("%s/api/v2/exchange-info", c.generalAPIEndpoint)
-	resp, err := c.sendGet(url, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return c.ExchangeInformationContext(context.Background())
+}
 
+func (c *client) ExchangeInformationContext(ctx context.Context) (*ExchangeInformationResp, error) {
+	url := fmt.Sprintf("%s/api/v2/exchange-info", c.generalAPIEndpoint)
 	var result ExchangeInformationResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.get(ctx, endpointExchangeInformation, url, &result); err != nil {
 		return nil, err
 	}
+	if c.limiter != nil {
+		c.limiter.Update(result.RateLimits)
+	}
 	return &result, nil
 }
 
-func (c *client) MarketPrice(symbol string) (MarketPriceResp, error) {
+func (c *client) MarketPrice(symbol Market) (MarketPriceResp, error) {
+	return c.MarketPriceContext(context.Background(), symbol)
+}
+
+func (c *client) MarketPriceContext(ctx context.Context, symbol Market) (MarketPriceResp, error) {
 	url := fmt.Sprintf("%s/api/v2/market-price", c.generalAPIEndpoint)
 	if symbol != "" {
-		url = fmt.Sprintf("%s?symbol=%s", url, symbol)
-	}
-	resp, err := c.sendGet(url, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
+		url = fmt.Sprintf("%s?symbol=%s", url, symbol.Symbol())
 	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
 	var result MarketPriceResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.get(ctx, endpointMarketPrice, url, &result); err != nil {
 		return nil, err
 	}
 	return result, nil
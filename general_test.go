@@ -1,6 +1,7 @@
 package kryptono
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -78,7 +79,7 @@ func TestServerTime(t *testing.T) {
 	}
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
-	assert.Equal(t, 1530682662257, resp.ServerTime)
+	assert.Equal(t, int64(1530682662257), resp.ServerTime.Millis())
 }
 
 func TestExchangeInformation(t *testing.T) {
@@ -143,7 +144,7 @@ func TestExchangeInformation(t *testing.T) {
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
 	assert.Equal(t, "UTC", resp.Timezone)
-	assert.Equal(t, 1530683054384, resp.ServerTime)
+	assert.Equal(t, int64(1530683054384), resp.ServerTime.Millis())
 
 	assert.Equal(t, 1, len(resp.RateLimits))
 	assert.Equal(t, "REQUESTS", resp.RateLimits[0].Type)
@@ -152,15 +153,15 @@ func TestExchangeInformation(t *testing.T) {
 
 	assert.Equal(t, 1, len(resp.BaseCurrencies))
 	assert.Equal(t, "KNOW", resp.BaseCurrencies[0].CurrencyCode)
-	assert.Equal(t, 100.0, resp.BaseCurrencies[0].MinimumTotalOrder)
+	assert.Equal(t, "100", resp.BaseCurrencies[0].MinimumTotalOrder.String())
 
 	assert.Equal(t, 1, len(resp.Coins))
 	assert.Equal(t, "USDT", resp.Coins[0].CurrencyCode)
 	assert.Equal(t, "Tether", resp.Coins[0].Name)
-	assert.Equal(t, 1.0, resp.Coins[0].MinimumOrderAmount)
+	assert.Equal(t, "1", resp.Coins[0].MinimumOrderAmount.String())
 
 	assert.Equal(t, 1, len(resp.Symbols))
-	assert.Equal(t, "GTO_ETH", resp.Symbols[0].Symbol)
+	assert.Equal(t, Market("GTO_ETH"), resp.Symbols[0].Symbol)
 	assert.Equal(t, 0.0, resp.Symbols[0].AmountLimitDecimal)
 	assert.Equal(t, 8.0, resp.Symbols[0].PriceLimitDecimal)
 	assert.Equal(t, true, resp.Symbols[0].AllowTrading)
@@ -209,11 +210,33 @@ func TestMarketPrice(t *testing.T) {
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
 	assert.Equal(t, 2, len(resp))
 
-	assert.Equal(t, "TRX_ETH", resp[0].Symbol)
-	assert.Equal(t, 0.00009317, resp[0].Price)
-	assert.Equal(t, 1574515989114, resp[0].UpdatedTime)
+	assert.Equal(t, Market("TRX_ETH"), resp[0].Symbol)
+	assert.Equal(t, "0.00009317", resp[0].Price.String())
+	assert.Equal(t, int64(1574515989114), resp[0].UpdatedTime.Millis())
 
-	assert.Equal(t, "SPIKE_BTC", resp[1].Symbol)
-	assert.Equal(t, 0.00000025, resp[1].Price)
-	assert.Equal(t, 1574515989127, resp[1].UpdatedTime)
+	assert.Equal(t, Market("SPIKE_BTC"), resp[1].Symbol)
+	assert.Equal(t, "0.00000025", resp[1].Price.String())
+	assert.Equal(t, int64(1574515989127), resp[1].UpdatedTime.Millis())
+}
+
+func TestPingContextCanceled(t *testing.T) {
+	pseudoAPIKey := uuid.NewV4()
+	pseudoAPISecret := "4a894c5c-8a7e-4337-bb6b-9fde16e3dddd"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not have been sent")
+	}))
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, pseudoAPIKey.String(), pseudoAPISecret)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, err := client.PingContext(ctx)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, context.Canceled, err)
 }
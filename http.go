@@ -2,13 +2,15 @@ package kryptono
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
 )
 
 const (
@@ -25,15 +27,19 @@ type response struct {
 	Body       io.ReadCloser
 	StatusCode int
 	Status     string
+	Endpoint   string
+	Method     string
+	URL        string
 }
 
+// checkHTTPStatus returns an *APIError if the response doesn't have one of the expected status codes.
 func checkHTTPStatus(resp response, expected ...int) error {
 	for _, e := range expected {
 		if resp.StatusCode == e {
 			return nil
 		}
 	}
-	return fmt.Errorf("http response status != %+v, got %d", expected, resp.StatusCode)
+	return newAPIError(resp)
 }
 
 func mergeHeaders(firstHeaders map[string]string, secondHeaders map[string]string) map[string]string {
@@ -51,112 +57,263 @@ func mergeHeaders(firstHeaders map[string]string, secondHeaders map[string]strin
 	return firstHeaders
 }
 
-func (c *client) sendPost(url string, additionalHeaders map[string]string, body io.Reader) (*response, error) {
+func (c *client) sendPost(ctx context.Context, ep endpoint, url string, additionalHeaders map[string]string, body io.Reader) (*response, error) {
 	bodyBytes, err := ioutil.ReadAll(body)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return &response{}, fmt.Errorf("error creating POST request, %v", err)
-	}
 
 	if additionalHeaders == nil {
 		additionalHeaders = make(map[string]string)
 	}
 
-	if c.auth != nil {
-		h := hmac.New(sha256.New, []byte(c.auth.APISecret))
-		h.Write(bodyBytes)
-		signature := hex.EncodeToString(h.Sum(nil))
+	if c.auth != nil || c.signer != nil {
+		signature, err := c.sign(ctx, bodyBytes)
+		if err != nil {
+			return nil, err
+		}
 		additionalHeaders[HeaderSignature] = signature
 	}
 
-	return c.sendRequest(req, additionalHeaders)
+	return c.sendRequest(ctx, ep, "POST", url, additionalHeaders, bodyBytes)
 }
 
-func (c *client) sendGet(url string, additionalHeaders map[string]string, body io.Reader) (*response, error) {
+func (c *client) sendGet(ctx context.Context, ep endpoint, url string, additionalHeaders map[string]string, body io.Reader) (*response, error) {
 	var err error
-	var req *http.Request
 	var bodyBytes []byte
 	if body != nil {
 		bodyBytes, err = ioutil.ReadAll(body)
 		if err != nil {
 			return nil, err
 		}
-		req, err = http.NewRequest("GET", url, bytes.NewReader(bodyBytes))
-	} else {
-		req, err = http.NewRequest("GET", url, nil)
-	}
-
-	if err != nil {
-		return &response{}, fmt.Errorf("error creating GET request, %v", err)
 	}
 
 	if additionalHeaders == nil {
 		additionalHeaders = make(map[string]string)
 	}
 
-	if bodyBytes != nil && c.auth != nil {
-		h := hmac.New(sha256.New, []byte(c.auth.APISecret))
-		h.Write(bodyBytes)
-		signature := hex.EncodeToString(h.Sum(nil))
+	if bodyBytes != nil && (c.auth != nil || c.signer != nil) {
+		signature, err := c.sign(ctx, bodyBytes)
+		if err != nil {
+			return nil, err
+		}
 		additionalHeaders[HeaderSignature] = signature
 	}
 
-	return c.sendRequest(req, additionalHeaders)
+	return c.sendRequest(ctx, ep, "GET", url, additionalHeaders, bodyBytes)
 }
 
-func (c *client) sendDelete(url string, additionalHeaders map[string]string, body io.Reader) (*response, error) {
+func (c *client) sendDelete(ctx context.Context, ep endpoint, url string, additionalHeaders map[string]string, body io.Reader) (*response, error) {
 	bodyBytes, err := ioutil.ReadAll(body)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("DELETE", url, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return &response{}, fmt.Errorf("error creating DELETE request, %v", err)
-	}
-
 	if additionalHeaders == nil {
 		additionalHeaders = make(map[string]string)
 	}
 
-	if c.auth != nil {
-		h := hmac.New(sha256.New, []byte(c.auth.APISecret))
-		h.Write(bodyBytes)
-		signature := hex.EncodeToString(h.Sum(nil))
+	if c.auth != nil || c.signer != nil {
+		signature, err := c.sign(ctx, bodyBytes)
+		if err != nil {
+			return nil, err
+		}
 		additionalHeaders[HeaderSignature] = signature
 	}
 
-	return c.sendRequest(req, additionalHeaders)
+	return c.sendRequest(ctx, ep, "DELETE", url, additionalHeaders, bodyBytes)
 }
 
-func (c *client) sendRequest(request *http.Request, additionalHeaders map[string]string) (*response, error) {
-
-	for k, v := range additionalHeaders {
-		request.Header.Add(k, v)
-	}
-
+// sendRequest sends the request and retries it according to the client's retry policy.
+func (c *client) sendRequest(ctx context.Context, ep endpoint, method string, url string, additionalHeaders map[string]string, bodyBytes []byte) (*response, error) {
 	thisHeaders := map[string]string{}
 	thisHeaders["Content-type"] = "application/json"
 	thisHeaders[HeaderXRequestedWith] = "XMLHttpRequest"
+	if c.userAgent != "" {
+		thisHeaders["User-Agent"] = c.userAgent
+	}
 	if c.auth != nil {
 		thisHeaders[HeaderAuthorization] = c.auth.APIKey
 	}
 	headers := mergeHeaders(additionalHeaders, thisHeaders)
+
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.acquire(ctx, ep); err != nil {
+				return nil, err
+			}
+		}
+		resp, sent, err := c.sendOnce(ctx, ep, method, url, headers, bodyBytes)
+		if err != nil {
+			// surface cancellation and deadlines as the plain context errors so
+			// callers can tell them apart from transport failures. A request that
+			// reached a non-idempotent endpoint may still have been executed.
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				if sent && !ep.idempotent {
+					return nil, &outcomeUnknownError{err: ctxErr}
+				}
+				return nil, ctxErr
+			}
+		}
+
+		wait, retry := c.retry.shouldRetry(ep, attempt, resp, sent, err)
+		if !retry {
+			if err != nil {
+				if sent && !ep.idempotent {
+					return nil, &outcomeUnknownError{err: err}
+				}
+				return nil, err
+			}
+			return resp, nil
+		}
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendOnce sends a single request. sent reports whether the request was completely
+// written to the connection, if it wasn't the server never saw it.
+func (c *client) sendOnce(ctx context.Context, ep endpoint, method string, url string, headers map[string]string, bodyBytes []byte) (resp *response, sent bool, err error) {
+	var body io.Reader
+	if bodyBytes != nil {
+		body = bytes.NewReader(bodyBytes)
+	}
+	// the request is written by the transport's own goroutine
+	var wrote int32
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				atomic.StoreInt32(&wrote, 1)
+			}
+		},
+	})
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, false, fmt.Errorf("error creating %s request, %v", method, err)
+	}
 	for k, v := range headers {
 		request.Header.Add(k, v)
 	}
-	resp, err := c.http.Do(request)
+
+	httpResp, err := c.do(ep, request)
 	if err != nil {
-		fmt.Println(fmt.Sprintf("erro: %v", err))
-		return nil, err
+		return nil, atomic.LoadInt32(&wrote) == 1, err
 	}
 	return &response{
-		StatusCode: resp.StatusCode,
-		Status:     resp.Status,
-		Header:     resp.Header,
-		Body:       resp.Body,
-	}, nil
+		StatusCode: httpResp.StatusCode,
+		Status:     httpResp.Status,
+		Header:     httpResp.Header,
+		Body:       httpResp.Body,
+		Endpoint:   ep.name,
+		Method:     method,
+		URL:        url,
+	}, true, nil
+}
+
+// DefaultMaxResponseSize is the default limit of the size of a response body.
+const DefaultMaxResponseSize = 10 << 20
+
+// maximum number of bytes read from an unread response body so its connection can be reused,
+// the connection of a larger rest is closed
+const maxDrainSize = 256 << 10
+
+// ErrResponseTooLarge is returned for a response body exceeding the client's maximum response size.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// WithMaxResponseSize limits the size of response bodies, larger responses fail with
+// ErrResponseTooLarge. It defaults to DefaultMaxResponseSize.
+func WithMaxResponseSize(size int64) ClientOption {
+	return func(c *client) error {
+		if size <= 0 {
+			return fmt.Errorf("max response size must be positive, got %d", size)
+		}
+		c.maxResponseSize = size
+		return nil
+	}
+}
+
+// get sends an unsigned GET request and decodes the response into result.
+func (c *client) get(ctx context.Context, ep endpoint, url string, result interface{}) error {
+	resp, err := c.sendGet(ctx, ep, url, nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.decodeResponse(resp, result)
+}
+
+// signed sends request as signed JSON body and decodes the response into result.
+func (c *client) signed(ctx context.Context, ep endpoint, method string, url string, request signedRequest, result interface{}) error {
+	resp, err := c.sendSigned(ctx, request, func(payload interface{}) (*response, error) {
+		return c.sendJSON(ctx, ep, method, url, payload)
+	})
+	if err != nil {
+		return err
+	}
+	return c.decodeResponse(resp, result)
+}
+
+// decodeResponse checks the status of resp and decodes its JSON body into result. The body is
+// always drained and closed, so the connection can be reused.
+func (c *client) decodeResponse(resp *response, result interface{}) error {
+	defer drainAndClose(resp.Body)
+	if err := checkHTTPStatus(*resp, http.StatusOK); err != nil {
+		return err
+	}
+
+	maxSize := c.maxResponseSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxResponseSize
+	}
+	body := &limitedReader{r: resp.Body, remaining: maxSize}
+	if c.schemaDrift != nil {
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			if body.exceeded {
+				return fmt.Errorf("%s: %w, limit is %d bytes", resp.Endpoint, ErrResponseTooLarge, maxSize)
+			}
+			return err
+		}
+		return c.decodeDrifting(resp.Endpoint, data, result)
+	}
+	if err := json.NewDecoder(body).Decode(result); err != nil {
+		if body.exceeded {
+			return fmt.Errorf("%s: %w, limit is %d bytes", resp.Endpoint, ErrResponseTooLarge, maxSize)
+		}
+		return err
+	}
+	return nil
+}
+
+// limitedReader reads from r until remaining bytes are read, then it fails.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// only fail if there actually is more
+		var b [1]byte
+		if n, _ := l.r.Read(b[:]); n > 0 {
+			l.exceeded = true
+			return 0, ErrResponseTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, io.LimitReader(body, maxDrainSize))
+	body.Close()
 }
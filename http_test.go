@@ -1,10 +1,18 @@
 package kryptono
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -52,7 +60,7 @@ func TestSendGet(t *testing.T) {
 		auth: auth,
 	}
 	headers := map[string]string{}
-	_, err := client.sendGet(fmt.Sprintf("%s/%s", ts.URL, "somePath"), headers, nil)
+	_, err := client.sendGet(context.Background(), endpointPing, fmt.Sprintf("%s/%s", ts.URL, "somePath"), headers, nil)
 	if err != nil {
 		t.Errorf("error in SendGet, %v", err)
 	}
@@ -73,8 +81,143 @@ func TestSendGetWithAdditionalHeaders(t *testing.T) {
 		http: &http.Client{},
 		auth: auth,
 	}
-	_, err := client.sendGet(fmt.Sprintf("%s/%s", ts.URL, "somePath"), nil, nil)
+	_, err := client.sendGet(context.Background(), endpointPing, fmt.Sprintf("%s/%s", ts.URL, "somePath"), nil, nil)
 	if err != nil {
 		t.Errorf("error in SendGet, %v", err)
 	}
 }
+
+func TestSendGetCanceled(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	client := &client{
+		http: &http.Client{},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err := client.sendGet(ctx, endpointPing, fmt.Sprintf("%s/%s", ts.URL, "somePath"), nil, nil)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestSendGetDeadlineExceeded(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	client := &client{
+		http: &http.Client{},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.sendGet(ctx, endpointPing, fmt.Sprintf("%s/%s", ts.URL, "somePath"), nil, nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+type closeTrackingTransport struct {
+	open int32
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	transport *closeTrackingTransport
+	closed    sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.closed.Do(func() {
+		atomic.AddInt32(&b.transport.open, -1)
+	})
+	return b.ReadCloser.Close()
+}
+
+func (t *closeTrackingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&t.open, 1)
+	resp.Body = &trackedBody{ReadCloser: resp.Body, transport: t}
+	return resp, nil
+}
+
+func TestResponseBodiesAreClosed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/time" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "INVALID_PARAMETER"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"result": true}`))
+	}))
+	defer ts.Close()
+
+	transport := &closeTrackingTransport{}
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithTransport(transport))
+	assert.Nil(t, err)
+
+	_, err = client.Ping()
+	assert.Nil(t, err)
+	_, err = client.ServerTime()
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&transport.open))
+}
+
+func TestConnectionReuseWhilePolling(t *testing.T) {
+	// trailing data after the JSON value is left unread by the decoder and has to be drained
+	body := `{"symbol": "KNOW_BTC", "limit": 100, "asks": [], "bids": [], "time": 1574517091326}` + strings.Repeat(" ", 8192)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	var connections int32
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, "key", "secret")
+	assert.Nil(t, err)
+
+	for i := 0; i < 200; i++ {
+		_, err := client.OrderBook("KNOW_BTC")
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"result": true, "padding": "` + strings.Repeat("x", 1024) + `"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithMaxResponseSize(1024))
+	assert.Nil(t, err)
+	_, err = client.Ping()
+	assert.True(t, errors.Is(err, ErrResponseTooLarge), err)
+
+	client, err = NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithMaxResponseSize(2048))
+	assert.Nil(t, err)
+	resp, err := client.Ping()
+	assert.Nil(t, err)
+	assert.True(t, resp.Result)
+
+	_, err = NewClient("key", "secret", WithMaxResponseSize(0))
+	assert.NotNil(t, err)
+}
@@ -1,8 +1,9 @@
 package kryptono
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
+	"time"
 )
 
 const (
@@ -18,7 +19,18 @@ func newClientWithURL(url string, apiKey string, apiSecret string) (Client, erro
 }
 
 func newClientWithURLs(apiKey string, apiSecret string, generalAPIEndpoint string, marketAPIEndpoint string, accountAPIEndpoint string, marketsAPIEndpoint string) (Client, error) {
-	return &client{
+	return NewClient(apiKey, apiSecret,
+		WithGeneralAPIEndpoint(generalAPIEndpoint),
+		WithMarketAPIEndpoint(marketAPIEndpoint),
+		WithAccountAPIEndpoint(accountAPIEndpoint),
+		WithMarketsAPIEndpoint(marketsAPIEndpoint),
+	)
+}
+
+// NewClient creates a new kryptono client with apiKey and apiSecret. Without options it talks to the
+// production endpoints using http.DefaultClient.
+func NewClient(apiKey string, apiSecret string, opts ...ClientOption) (Client, error) {
+	c := &client{
 		http: http.DefaultClient,
 		auth: &auth{
 			APIKey:    apiKey,
@@ -28,21 +40,39 @@ func newClientWithURLs(apiKey string, apiSecret string, generalAPIEndpoint strin
 		marketAPIEndpoint:  marketAPIEndpoint,
 		accountAPIEndpoint: accountAPIEndpoint,
 		marketsAPIEndpoint: marketsAPIEndpoint,
-	}, nil
-}
+		recvWindow:         DefaultRecvWindow,
+		maxResponseSize:    DefaultMaxResponseSize,
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
 
-// NewClient creates a new kryptono client with apiKey and apiSecret
-func NewClient(apiKey string, apiSecret string) (Client, error) {
-	return newClientWithURLs(apiKey, apiSecret, generalAPIEndpoint, marketAPIEndpoint, accountAPIEndpoint, marketsAPIEndpoint)
+	// never modify a http.Client handed in by the caller
+	if c.transport != nil || c.timeout > 0 {
+		httpClient := *c.http
+		if c.transport != nil {
+			httpClient.Transport = c.transport
+		}
+		if c.timeout > 0 {
+			httpClient.Timeout = c.timeout
+		}
+		c.http = &httpClient
+	}
+	return c, nil
 }
 
+// Client is the kryptono API client. Every method has a ...Context variant that
+// passes ctx down to the HTTP request, so cancellation and deadlines abort the
+// call and are returned as context.Canceled or context.DeadlineExceeded.
 type Client interface {
 	Ping() (*PingResp, error)
 	ServerTime() (*ServerTimeResp, error)
 	ExchangeInformation() (*ExchangeInformationResp, error)
-	MarketPrice(symbol string) (MarketPriceResp, error)
-	TradeHistory(symbol string) (*TradeHistoryResp, error)
-	OrderBook(symbol string) (*OrderBookResp, error)
+	MarketPrice(symbol Market) (MarketPriceResp, error)
+	TradeHistory(symbol Market) (*TradeHistoryResp, error)
+	OrderBook(symbol Market) (*OrderBookResp, error)
 	MarketSummaries() (*MarketSummariesResp, error)
 	NewOrder(request *NewOrderRequest) (*NewOrderResp, error)
 	TestNewOrder(request *NewOrderRequest) (*TestNewOrderResp, error)
@@ -55,6 +85,24 @@ type Client interface {
 	TradeList(request *TradeListRequest) (*TradeListResp, error)
 	AccountInformation(request *AccountInformationRequest) (*AccountInformationResp, error)
 	AccountBalances(request *AccountBalancesRequest) (*AccountBalancesResp, error)
+	PingContext(ctx context.Context) (*PingResp, error)
+	ServerTimeContext(ctx context.Context) (*ServerTimeResp, error)
+	ExchangeInformationContext(ctx context.Context) (*ExchangeInformationResp, error)
+	MarketPriceContext(ctx context.Context, symbol Market) (MarketPriceResp, error)
+	TradeHistoryContext(ctx context.Context, symbol Market) (*TradeHistoryResp, error)
+	OrderBookContext(ctx context.Context, symbol Market) (*OrderBookResp, error)
+	MarketSummariesContext(ctx context.Context) (*MarketSummariesResp, error)
+	NewOrderContext(ctx context.Context, request *NewOrderRequest) (*NewOrderResp, error)
+	TestNewOrderContext(ctx context.Context, request *NewOrderRequest) (*TestNewOrderResp, error)
+	OrderDetailContext(ctx context.Context, request *OrderDetailRequest) (*OrderDetailResp, error)
+	CancelOrderContext(ctx context.Context, request *CancelOrderRequest) (*CancelOrderResp, error)
+	TradeDetailsContext(ctx context.Context, request *TradeDetailsRequest) (*TradeDetailsResp, error)
+	OpenOrdersContext(ctx context.Context, request *OpenOrdersRequest) (*OpenOrdersResp, error)
+	CompletedOrdersContext(ctx context.Context, request *CompletedOrdersRequest) (*CompletedOrdersResp, error)
+	AllOrdersContext(ctx context.Context, request *AllOrdersRequest) (*AllOrdersResp, error)
+	TradeListContext(ctx context.Context, request *TradeListRequest) (*TradeListResp, error)
+	AccountInformationContext(ctx context.Context, request *AccountInformationRequest) (*AccountInformationResp, error)
+	AccountBalancesContext(ctx context.Context, request *AccountBalancesRequest) (*AccountBalancesResp, error)
 }
 
 type auth struct {
@@ -69,25 +117,18 @@ type client struct {
 	marketAPIEndpoint  string
 	accountAPIEndpoint string
 	marketsAPIEndpoint string
-}
-
-type Float64Pair [2]float64
-
-func (pair *Float64Pair) UnmarshalJSON(b []byte) error {
-	tmp := []json.Number{}
-	if err := json.Unmarshal(b, &tmp); err != nil {
-		return err
-	}
-
-	left, err := tmp[0].Float64()
-	if err != nil {
-		return err
-	}
-	right, err := tmp[1].Float64()
-	if err != nil {
-		return err
-	}
-	*pair = Float64Pair{left, right}
-
-	return nil
+	transport          http.RoundTripper
+	timeout            time.Duration
+	userAgent          string
+	retry              *RetryPolicy
+	limiter            *RateLimiter
+	recvWindow         time.Duration
+	clock              serverClock
+	middlewares        []Middleware
+	logger             Logger
+	wireDump           bool
+	signer             Signer
+	maxResponseSize    int64
+	schemaDrift        func(drift SchemaDrift)
+	validators         func(ctx context.Context) (*OrderValidator, error)
 }
@@ -3,10 +3,17 @@ package kryptono
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 )
 
 // isEqualJSON checks two json strings for equality
 func isEqualJSON(s1 string, s2 string) (bool, error) {
+	return isEqualJSONFold(s1, s2)
+}
+
+// isEqualJSONFold checks two json strings for equality, comparing the string values of keys
+// case-insensitively
+func isEqualJSONFold(s1 string, s2 string, keys ...string) (bool, error) {
 	var o1 interface{}
 	var o2 interface{}
 
@@ -20,5 +27,27 @@ func isEqualJSON(s1 string, s2 string) (bool, error) {
 		return false, err
 	}
 
-	return reflect.DeepEqual(o1, o2), nil
+	return reflect.DeepEqual(foldJSON(o1, keys), foldJSON(o2, keys)), nil
+}
+
+// foldJSON lower cases the string values of keys in the decoded json value v
+func foldJSON(v interface{}, keys []string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if s, ok := value.(string); ok {
+				for _, key := range keys {
+					if k == key {
+						value = strings.ToLower(s)
+					}
+				}
+			}
+			v[k] = foldJSON(value, keys)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = foldJSON(v[i], keys)
+		}
+	}
+	return v
 }
@@ -0,0 +1,148 @@
+package kryptono
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"time"
+)
+
+// Logger receives the client's log output. keyvals are alternating keys and values, as with
+// log/slog. *slog.Logger implements Logger.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// NewSlogLogger returns a Logger writing to logger, or to slog.Default() if logger is nil.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger
+}
+
+// WithLogger sets the logger of the client. Without it the client doesn't log. Requests and
+// responses are logged at debug level, failed requests at warn level.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *client) error {
+		if logger == nil {
+			return errors.New("logger must not be nil")
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithWireDump adds dumps of requests and responses, including bodies, to the debug log.
+// Credentials are redacted from the dumps, response bodies are cut off after 16 KiB.
+func WithWireDump() ClientOption {
+	return func(c *client) error {
+		c.wireDump = true
+		return nil
+	}
+}
+
+const redacted = "[REDACTED]"
+
+// maximum number of bytes of a response body included in a wire dump
+const maxDumpBodySize = 16 << 10
+
+// logged wraps next to log every request and its response.
+func (c *client) logged(next Handler) Handler {
+	return func(endpoint string, request *http.Request) (*http.Response, error) {
+		if c.logger == nil {
+			return next(endpoint, request)
+		}
+
+		keyvals := []interface{}{"endpoint", endpoint, "method", request.Method, "url", c.redact(request.URL.String())}
+		if c.wireDump {
+			keyvals = append(keyvals, "dump", c.dumpRequest(request))
+		}
+		c.logger.Debug("sending request", keyvals...)
+
+		start := time.Now()
+		resp, err := next(endpoint, request)
+		duration := time.Since(start)
+		if err != nil {
+			c.logger.Warn("request failed", "endpoint", endpoint, "method", request.Method, "url", c.redact(request.URL.String()),
+				"duration", duration, "error", c.redact(err.Error()))
+			return nil, err
+		}
+
+		keyvals = []interface{}{"endpoint", endpoint, "status", resp.StatusCode, "duration", duration}
+		if c.wireDump {
+			// a failed dump is logged, it doesn't fail the request
+			dump, err := c.dumpResponse(resp)
+			if err != nil {
+				keyvals = append(keyvals, "dump_error", c.redact(err.Error()))
+			}
+			keyvals = append(keyvals, "dump", c.redact(dump))
+		}
+		c.logger.Debug("received response", keyvals...)
+		return resp, nil
+	}
+}
+
+// dumpRequest dumps the request with redacted credentials.
+func (c *client) dumpRequest(request *http.Request) string {
+	clone := request.Clone(request.Context())
+	clone.Header = redactHeader(request.Header)
+	if request.GetBody != nil {
+		if body, err := request.GetBody(); err == nil {
+			clone.Body = body
+		}
+	}
+	dump, err := httputil.DumpRequestOut(clone, true)
+	if err != nil {
+		return err.Error()
+	}
+	return c.redact(string(dump))
+}
+
+// dumpResponse dumps the response with at most maxDumpBodySize bytes of its body. The body
+// read for the dump is put back, so resp is unchanged for the caller.
+func (c *client) dumpResponse(resp *http.Response) (string, error) {
+	dump, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return "", err
+	}
+	limit := int64(maxDumpBodySize)
+	if c.maxResponseSize > 0 && c.maxResponseSize < limit {
+		limit = c.maxResponseSize
+	}
+	body, truncated, err := peekBody(resp, limit)
+	dump = append(dump, body...)
+	if truncated {
+		dump = append(dump, "\n[TRUNCATED]"...)
+	}
+	return string(dump), err
+}
+
+// redactHeader returns a copy of header without the values of the credential headers.
+func redactHeader(header http.Header) http.Header {
+	clone := header.Clone()
+	for _, key := range []string{HeaderAuthorization, HeaderSignature} {
+		if clone.Get(key) != "" {
+			clone.Set(key, redacted)
+		}
+	}
+	return clone
+}
+
+// redact removes the API key and secret from s.
+func (c *client) redact(s string) string {
+	if c.auth == nil {
+		return s
+	}
+	for _, credential := range []string{c.auth.APISecret, c.auth.APIKey} {
+		if credential != "" {
+			s = strings.ReplaceAll(s, credential, redacted)
+		}
+	}
+	return s
+}
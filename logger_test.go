@@ -0,0 +1,135 @@
+package kryptono
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerRedactsCredentials(t *testing.T) {
+	apiKey := "cd1b49c6-0d8f-4c26-9c9a-6b5a2c4a1e11"
+	apiSecret := "4a894c5c-8a7e-4337-bb6b-9fde16e3dddd"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		// a response echoing the key must not leak it either
+		w.Write([]byte(`[{"currency_code": "BTC", "address": "` + apiKey + `"}]`))
+	}))
+	defer ts.Close()
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := NewClient(apiKey, apiSecret, WithAccountAPIEndpoint(ts.URL), WithLogger(NewSlogLogger(logger)), WithWireDump())
+	assert.Nil(t, err)
+
+	_, err = client.AccountBalances(&AccountBalancesRequest{Timestamp: NewTimestampFromMillis(1530682938651), RecvWindow: 5000})
+	assert.Nil(t, err)
+
+	log := out.String()
+	assert.Contains(t, log, "sending request")
+	assert.Contains(t, log, "received response")
+	assert.Contains(t, log, "endpoint=AccountBalances")
+	assert.Contains(t, log, "status=200")
+	assert.Contains(t, log, "1530682938651")
+	assert.Contains(t, log, redacted)
+	assert.NotContains(t, log, apiKey)
+	assert.NotContains(t, log, apiSecret)
+	assert.NotContains(t, log, "f6f8463e8c41574ccb2bfc46938868b78b0b6a1a0cb75cf58a92eba3d6535b9f")
+}
+
+func TestLoggerLogsFailedRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	url := "http://" + listener.Addr().String()
+	listener.Close()
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(url), WithLogger(logger))
+	assert.Nil(t, err)
+
+	_, err = client.Ping()
+	assert.NotNil(t, err)
+
+	log := out.String()
+	assert.Contains(t, log, "level=WARN")
+	assert.Contains(t, log, "request failed")
+	assert.Contains(t, log, "endpoint=Ping")
+	// debug output is filtered by the handler's level
+	assert.False(t, strings.Contains(log, "sending request"))
+}
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set(HeaderAuthorization, "key")
+	header.Set(HeaderSignature, "signature")
+	header.Set("Content-Type", "application/json")
+
+	clone := redactHeader(header)
+	assert.Equal(t, redacted, clone.Get(HeaderAuthorization))
+	assert.Equal(t, redacted, clone.Get(HeaderSignature))
+	assert.Equal(t, "application/json", clone.Get("Content-Type"))
+	assert.Equal(t, "key", header.Get(HeaderAuthorization))
+}
+
+func TestWireDumpTruncatesResponseBody(t *testing.T) {
+	body := `[{"currency_code": "BTC", "address": "` + strings.Repeat("a", 2*maxDumpBodySize) + `"}]`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithLogger(logger), WithWireDump())
+	assert.Nil(t, err)
+
+	resp, err := client.AccountBalances(&AccountBalancesRequest{})
+	assert.Nil(t, err)
+	// the whole body is still decoded
+	assert.Equal(t, 2*maxDumpBodySize, len((*resp)[0].Address))
+	assert.Contains(t, out.String(), "[TRUNCATED]")
+	assert.Less(t, out.Len(), 2*maxDumpBodySize)
+}
+
+type failingBody struct {
+	closed bool
+}
+
+func (b *failingBody) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func (b *failingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestWireDumpFailureKeepsResponse(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := &client{logger: logger, wireDump: true}
+
+	body := &failingBody{}
+	request := httptest.NewRequest(http.MethodPost, "http://localhost/api/v2/order/add", nil)
+	handler := c.logged(func(endpoint string, request *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body, ProtoMajor: 1, ProtoMinor: 1}, nil
+	})
+
+	resp, err := handler("NewOrder", request)
+	assert.Nil(t, err)
+	assert.NotNil(t, resp)
+	assert.Contains(t, out.String(), "dump_error")
+	assert.Contains(t, out.String(), "connection reset")
+	// the body is handed on, not closed
+	assert.False(t, body.closed)
+	resp.Body.Close()
+	assert.True(t, body.closed)
+}
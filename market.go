@@ -1,33 +1,31 @@
 package kryptono
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 )
 
 type TradeHistoryResp struct {
-	Symbol  string    `json:"symbol"`
+	Symbol  Market    `json:"symbol"`
 	Limit   int       `json:"limit"`
 	History []History `json:"history"`
-	Time    int       `json:"time"`
+	Time    Timestamp `json:"time"`
 }
 
 type History struct {
-	ID           int     `json:"id"`
-	Price        float64 `json:"price,string"`
-	Qty          float64 `json:"qty,string"`
-	IsBuyerMaker bool    `json:"isBuyerMaker"`
-	Time         int     `json:"time"`
+	ID           int       `json:"id"`
+	Price        Decimal   `json:"price"`
+	Qty          Decimal   `json:"qty"`
+	IsBuyerMaker bool      `json:"isBuyerMaker"`
+	Time         Timestamp `json:"time"`
 }
 
 type OrderBookResp struct {
-	Symbol string        `json:"symbol"`
-	Asks   []Float64Pair `json:"asks"`
-	Limit  int           `json:"limit"`
-	Bids   []Float64Pair `json:"bids"`
-	Time   int           `json:"time"`
+	Symbol Market       `json:"symbol"`
+	Asks   []PriceLevel `json:"asks"`
+	Limit  int          `json:"limit"`
+	Bids   []PriceLevel `json:"bids"`
+	Time   Timestamp    `json:"time"`
 }
 
 type MarketSummariesResp struct {
@@ -35,94 +33,86 @@ type MarketSummariesResp struct {
 	Message string                       `json:"message"`
 	Result  []MarketSummariesRespElement `json:"result"`
 	Volumes []Volume                     `json:"volumes"`
-	T       int                          `json:"t"`
+	T       Timestamp                    `json:"t"`
 }
 
 type MarketSummariesRespElement struct {
-	MarketName string      `json:"MarketName"`
-	High       float64     `json:"High"`
-	Low        float64     `json:"Low"`
-	BaseVolume float64     `json:"BaseVolume"`
-	Last       float64     `json:"Last"`
-	TimeStamp  string      `json:"TimeStamp"`
-	Volume     float64     `json:"Volume"`
-	Bid        interface{} `json:"Bid"`
-	Ask        interface{} `json:"Ask"`
-	PrevDay    float64     `json:"PrevDay"`
+	MarketName Market      `json:"MarketName"`
+	High       Decimal     `json:"High"`
+	Low        Decimal     `json:"Low"`
+	BaseVolume Decimal     `json:"BaseVolume"`
+	Last       Decimal     `json:"Last"`
+	TimeStamp  Timestamp   `json:"TimeStamp"`
+	Volume     Decimal     `json:"Volume"`
+	Bid        NullDecimal `json:"Bid"`
+	Ask        NullDecimal `json:"Ask"`
+	PrevDay    Decimal     `json:"PrevDay"`
 }
 
 type Volume struct {
 	CoinName string  `json:"CoinName"`
-	Volume   float64 `json:"Volume"`
+	Volume   Decimal `json:"Volume"`
 }
 
-func (c *client) TradeHistory(symbol string) (*TradeHistoryResp, error) {
-	url := fmt.Sprintf("%s/api/v1/ht?symbol=%s", c.marketAPIEndpoint, symbol)
-	resp, err := c.sendGet(url, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
+// number of decimal places of ChangePercent
+const changePercentScale = 2
+
+// Change returns the change of the last price in the last 24 hours.
+func (e MarketSummariesRespElement) Change() Decimal {
+	return e.Last.Sub(e.PrevDay)
+}
+
+// ChangePercent returns the change of the last price in the last 24 hours in percent, rounded to
+// 2 decimal places. ok is false if there is no previous price.
+func (e MarketSummariesRespElement) ChangePercent() (Decimal, bool) {
+	if e.PrevDay.Sign() <= 0 {
+		return Decimal{}, false
 	}
+	return e.Change().Mul(NewDecimalFromInt(100)).Div(e.PrevDay, changePercentScale), true
+}
 
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+// Spread returns ask minus bid, ok is false if one of them is missing.
+func (e MarketSummariesRespElement) Spread() (Decimal, bool) {
+	if !e.Bid.Valid || !e.Ask.Valid {
+		return Decimal{}, false
 	}
+	return e.Ask.Decimal.Sub(e.Bid.Decimal), true
+}
+
+func (c *client) TradeHistory(symbol Market) (*TradeHistoryResp, error) {
+	return c.TradeHistoryContext(context.Background(), symbol)
+}
 
+func (c *client) TradeHistoryContext(ctx context.Context, symbol Market) (*TradeHistoryResp, error) {
+	url := fmt.Sprintf("%s/api/v1/ht?symbol=%s", c.marketAPIEndpoint, symbol.Symbol())
 	var result TradeHistoryResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.get(ctx, endpointTradeHistory, url, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-func (c *client) OrderBook(symbol string) (*OrderBookResp, error) {
-	url := fmt.Sprintf("%s/api/v1/dp?symbol=%s", c.marketAPIEndpoint, symbol)
-	resp, err := c.sendGet(url, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+func (c *client) OrderBook(symbol Market) (*OrderBookResp, error) {
+	return c.OrderBookContext(context.Background(), symbol)
+}
 
+func (c *client) OrderBookContext(ctx context.Context, symbol Market) (*OrderBookResp, error) {
+	url := fmt.Sprintf("%s/api/v1/dp?symbol=%s", c.marketAPIEndpoint, symbol.Symbol())
 	var result OrderBookResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.get(ctx, endpointOrderBook, url, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 func (c *client) MarketSummaries() (*MarketSummariesResp, error) {
-	url := fmt.Sprintf("%s/v1/getmarketsummaries", c.marketsAPIEndpoint)
-	resp, err := c.sendGet(url, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	err = checkHTTPStatus(*resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return c.MarketSummariesContext(context.Background())
+}
 
+func (c *client) MarketSummariesContext(ctx context.Context) (*MarketSummariesResp, error) {
+	url := fmt.Sprintf("%s/v1/getmarketsummaries", c.marketsAPIEndpoint)
 	var result MarketSummariesResp
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
+	if err := c.get(ctx, endpointMarketSummaries, url, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -1,6 +1,7 @@
 package kryptono
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -53,16 +54,16 @@ func TestTradeHistory(t *testing.T) {
 	}
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
-	assert.Equal(t, "KNOW_BTC", resp.Symbol)
+	assert.Equal(t, Market("KNOW_BTC"), resp.Symbol)
 	assert.Equal(t, 100, resp.Limit)
-	assert.Equal(t, 1529298130192, resp.Time)
+	assert.Equal(t, int64(1529298130192), resp.Time.Millis())
 
 	assert.Equal(t, 1, len(resp.History))
 	assert.Equal(t, 139638, resp.History[0].ID)
-	assert.Equal(t, 0.00001723, resp.History[0].Price)
-	assert.Equal(t, 81.00000000, resp.History[0].Qty)
+	assert.Equal(t, "0.00001723", resp.History[0].Price.String())
+	assert.Equal(t, "81.00000000", resp.History[0].Qty.String())
 	assert.Equal(t, false, resp.History[0].IsBuyerMaker)
-	assert.Equal(t, 1529262196270, resp.History[0].Time)
+	assert.Equal(t, int64(1529262196270), resp.History[0].Time.Millis())
 }
 
 func TestOrderBook(t *testing.T) {
@@ -111,17 +112,17 @@ func TestOrderBook(t *testing.T) {
 	}
 
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
-	assert.Equal(t, "KNOW_BTC", resp.Symbol)
+	assert.Equal(t, Market("KNOW_BTC"), resp.Symbol)
 	assert.Equal(t, 100, resp.Limit)
-	assert.Equal(t, 1574517091326, resp.Time)
+	assert.Equal(t, int64(1574517091326), resp.Time.Millis())
 
 	assert.Equal(t, 1, len(resp.Asks))
-	assert.Equal(t, 0.00000035, resp.Asks[0][0])
-	assert.Equal(t, 17790.00000000, resp.Asks[0][1])
+	assert.Equal(t, "0.00000035", resp.Asks[0].Price.String())
+	assert.Equal(t, "17790.00000000", resp.Asks[0].Size.String())
 
 	assert.Equal(t, 1, len(resp.Bids))
-	assert.Equal(t, 0.00000019, resp.Bids[0][0])
-	assert.Equal(t, 21052.00000000, resp.Bids[0][1])
+	assert.Equal(t, "0.00000019", resp.Bids[0].Price.String())
+	assert.Equal(t, "21052.00000000", resp.Bids[0].Size.String())
 }
 
 func TestMarketSummaries(t *testing.T) {
@@ -192,4 +193,45 @@ func TestMarketSummaries(t *testing.T) {
 	assert.NotNil(t, resp, fmt.Sprintf("error: %v", err))
 	assert.Equal(t, true, resp.Success)
 	assert.Equal(t, "", resp.Message)
+
+	eos := resp.Result[0]
+	assert.True(t, eos.Bid.Valid)
+	assert.Equal(t, "0.00035120", eos.Bid.Decimal.String())
+	assert.Equal(t, "0.0000031", eos.Change().String())
+	change, ok := eos.ChangePercent()
+	assert.True(t, ok)
+	assert.Equal(t, "0.87", change.String())
+	spread, ok := eos.Spread()
+	assert.True(t, ok)
+	assert.Equal(t, "0.00001760", spread.String())
+
+	lyl := resp.Result[1]
+	assert.Equal(t, "0.00000004", lyl.Ask.Decimal.String())
+	assert.True(t, lyl.Change().IsZero())
+}
+
+func TestMarketSummariesNullBidAsk(t *testing.T) {
+	var element MarketSummariesRespElement
+	assert.Nil(t, json.Unmarshal([]byte(`{"Bid": null, "Ask": "", "Last": 1, "PrevDay": 0}`), &element))
+	assert.False(t, element.Bid.Valid)
+	assert.False(t, element.Ask.Valid)
+	_, ok := element.Spread()
+	assert.False(t, ok)
+	_, ok = element.ChangePercent()
+	assert.False(t, ok)
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"Bid": 0.1, "Ask": "0.20"}`), &element))
+	spread, ok := element.Spread()
+	assert.True(t, ok)
+	assert.Equal(t, "0.10", spread.String())
+
+	output, err := json.Marshal(struct {
+		Bid  NullDecimal `json:"bid"`
+		Ask  NullDecimal `json:"ask"`
+		Last NullDecimal `json:"last"`
+	}{element.Bid, NullDecimal{}, NewNullDecimal(MustParseDecimal("1.50"))})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"bid":0.1,"ask":null,"last":"1.50"}`, string(output))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"Bid": "n/a"}`), &element))
 }
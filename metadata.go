@@ -0,0 +1,333 @@
+package kryptono
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetadataSource provides the exchange information, Client is one.
+type MetadataSource interface {
+	ExchangeInformationContext(ctx context.Context) (*ExchangeInformationResp, error)
+}
+
+// MetadataChangeKind is the kind of change of a symbol between two refreshes.
+type MetadataChangeKind string
+
+const (
+	// SymbolAdded is a symbol listed since the last refresh.
+	SymbolAdded MetadataChangeKind = "symbol_added"
+	// SymbolRemoved is a symbol not listed anymore.
+	SymbolRemoved MetadataChangeKind = "symbol_removed"
+	// TradingDisabled is a symbol not allowing trading anymore.
+	TradingDisabled MetadataChangeKind = "trading_disabled"
+	// TradingEnabled is a symbol allowing trading again.
+	TradingEnabled MetadataChangeKind = "trading_enabled"
+)
+
+// MetadataChange is a change of a symbol between two refreshes.
+type MetadataChange struct {
+	Kind   MetadataChangeKind
+	Symbol Symbol
+}
+
+// MetadataCache caches ExchangeInformation and indexes it. Once the information is older than
+// ttl, accesses return it right away and refresh it in the background; only the first access
+// waits for a fetch. Concurrent accesses share a single fetch. After a failed fetch, no fetch is
+// started for a backoff of a second, doubling up to ttl. It is safe for concurrent use.
+type MetadataCache struct {
+	source MetadataSource
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	current  *metadataSnapshot
+	fetched  time.Time
+	inflight *metadataFetch
+	handlers []func(change MetadataChange)
+	// failures counts the fetches failed in a row, the last one failed with err
+	failures int
+	err      error
+	retryAt  time.Time
+}
+
+// metadataSnapshot is the exchange information of one fetch with its indexes.
+type metadataSnapshot struct {
+	info           *ExchangeInformationResp
+	rules          *exchangeRules
+	coins          map[string]Coin
+	baseCurrencies map[string]BaseCurrency
+	byBase         map[string][]Symbol
+	byQuote        map[string][]Symbol
+	tradable       []Symbol
+}
+
+// longest time a shared fetch of the exchange information may take
+const metadataFetchTimeout = 30 * time.Second
+
+// time no fetch is started after the first failed fetch, it doubles with every further failure
+const metadataRetryBackoff = time.Second
+
+type metadataFetch struct {
+	done     chan struct{}
+	snapshot *metadataSnapshot
+	err      error
+}
+
+// NewMetadataCache returns a cache of the exchange information of source, refreshed after ttl.
+func NewMetadataCache(source MetadataSource, ttl time.Duration) *MetadataCache {
+	return &MetadataCache{source: source, ttl: ttl, now: time.Now}
+}
+
+// OnChange registers handler to be called for every change of a symbol found by a refresh.
+// The first fetch doesn't raise changes. Handlers are called from the fetch, before the calls waiting for it return.
+func (m *MetadataCache) OnChange(handler func(change MetadataChange)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// Refresh fetches the exchange information now.
+func (m *MetadataCache) Refresh(ctx context.Context) error {
+	_, err := m.fetch(ctx)
+	return err
+}
+
+// ExchangeInformation returns the cached exchange information, don't modify it.
+func (m *MetadataCache) ExchangeInformation(ctx context.Context) (*ExchangeInformationResp, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.info, nil
+}
+
+// Symbol returns the rules of market, or an error wrapping ErrNotFound if it isn't listed.
+func (m *MetadataCache) Symbol(ctx context.Context, market Market) (Symbol, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return Symbol{}, err
+	}
+	symbol, ok := snapshot.rules.symbol(market)
+	if !ok {
+		return Symbol{}, fmt.Errorf("%w: market %s is not listed", ErrNotFound, market)
+	}
+	return symbol, nil
+}
+
+// Coin returns the coin with currency code, or an error wrapping ErrNotFound if it isn't listed.
+func (m *MetadataCache) Coin(ctx context.Context, code string) (Coin, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return Coin{}, err
+	}
+	coin, ok := snapshot.coins[strings.ToUpper(code)]
+	if !ok {
+		return Coin{}, fmt.Errorf("%w: coin %s is not listed", ErrNotFound, code)
+	}
+	return coin, nil
+}
+
+// BaseCurrency returns the base currency with currency code, or an error wrapping ErrNotFound if it isn't listed.
+func (m *MetadataCache) BaseCurrency(ctx context.Context, code string) (BaseCurrency, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return BaseCurrency{}, err
+	}
+	currency, ok := snapshot.baseCurrencies[strings.ToUpper(code)]
+	if !ok {
+		return BaseCurrency{}, fmt.Errorf("%w: base currency %s is not listed", ErrNotFound, code)
+	}
+	return currency, nil
+}
+
+// MarketsByBase returns the symbols of all markets trading currency, e.g. KNOW_BTC and KNOW_ETH for KNOW.
+func (m *MetadataCache) MarketsByBase(ctx context.Context, currency string) ([]Symbol, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Symbol(nil), snapshot.byBase[strings.ToUpper(currency)]...), nil
+}
+
+// MarketsByQuote returns the symbols of all markets priced in currency, e.g. KNOW_BTC and EOS_BTC for BTC.
+// The exchange calls these currencies base currencies.
+func (m *MetadataCache) MarketsByQuote(ctx context.Context, currency string) ([]Symbol, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Symbol(nil), snapshot.byQuote[strings.ToUpper(currency)]...), nil
+}
+
+// TradableSymbols returns the symbols of all markets allowing trading.
+func (m *MetadataCache) TradableSymbols(ctx context.Context) ([]Symbol, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Symbol(nil), snapshot.tradable...), nil
+}
+
+// OrderValidator returns a validator for the current rules.
+func (m *MetadataCache) OrderValidator(ctx context.Context) (*OrderValidator, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderValidator{rules: snapshot.rules}, nil
+}
+
+// Quantizer returns a quantizer for the current rules.
+func (m *MetadataCache) Quantizer(ctx context.Context) (*Quantizer, error) {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Quantizer{rules: snapshot.rules}, nil
+}
+
+// snapshot returns the current snapshot. A snapshot older than ttl is returned as is and refreshed
+// in the background. Without a snapshot, it is fetched unless the last fetch failed within the backoff.
+func (m *MetadataCache) snapshot(ctx context.Context) (*metadataSnapshot, error) {
+	m.mu.Lock()
+	current, err := m.current, m.err
+	now := m.now()
+	stale := now.Sub(m.fetched) >= m.ttl
+	backoff := now.Before(m.retryAt)
+	m.mu.Unlock()
+
+	switch {
+	case current != nil:
+		if stale && !backoff {
+			m.start(ctx)
+		}
+		return current, nil
+	case backoff:
+		return nil, err
+	default:
+		return m.fetch(ctx)
+	}
+}
+
+// fetch fetches a new snapshot, or waits for the fetch already running. The fetch isn't tied to
+// ctx, so a caller giving up doesn't fail it for the others waiting.
+func (m *MetadataCache) fetch(ctx context.Context) (*metadataSnapshot, error) {
+	f := m.start(ctx)
+	select {
+	case <-f.done:
+		return f.snapshot, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// start starts a fetch, unless one is running already, and returns it.
+func (m *MetadataCache) start(ctx context.Context) *metadataFetch {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f := m.inflight
+	if f == nil {
+		f = &metadataFetch{done: make(chan struct{})}
+		m.inflight = f
+		go m.run(context.WithoutCancel(ctx), f)
+	}
+	return f
+}
+
+// run fetches the snapshot of f and reports the changes before completing f.
+func (m *MetadataCache) run(ctx context.Context, f *metadataFetch) {
+	ctx, cancel := context.WithTimeout(ctx, metadataFetchTimeout)
+	defer cancel()
+	info, err := m.source.ExchangeInformationContext(ctx)
+	if err == nil {
+		f.snapshot = newMetadataSnapshot(info)
+	}
+	f.err = err
+
+	m.mu.Lock()
+	m.inflight = nil
+	var changes []MetadataChange
+	if err == nil {
+		if m.current != nil {
+			changes = diffSymbols(m.current, f.snapshot)
+		}
+		m.current = f.snapshot
+		m.fetched = m.now()
+		m.failures, m.err, m.retryAt = 0, nil, time.Time{}
+	} else {
+		m.failures++
+		m.err = err
+		m.retryAt = m.now().Add(m.backoff())
+	}
+	handlers := append([]func(change MetadataChange){}, m.handlers...)
+	m.mu.Unlock()
+
+	for _, change := range changes {
+		for _, handler := range handlers {
+			handler(change)
+		}
+	}
+	close(f.done)
+}
+
+// backoff returns the time to wait after the failures so far, doubling from metadataRetryBackoff up to ttl.
+func (m *MetadataCache) backoff() time.Duration {
+	backoff := metadataRetryBackoff
+	for i := 1; i < m.failures && backoff < m.ttl; i++ {
+		backoff *= 2
+	}
+	if backoff > m.ttl && m.ttl > metadataRetryBackoff {
+		backoff = m.ttl
+	}
+	return backoff
+}
+
+func newMetadataSnapshot(info *ExchangeInformationResp) *metadataSnapshot {
+	s := &metadataSnapshot{
+		info:           info,
+		rules:          newExchangeRules(info),
+		coins:          make(map[string]Coin, len(info.Coins)),
+		baseCurrencies: make(map[string]BaseCurrency, len(info.BaseCurrencies)),
+		byBase:         make(map[string][]Symbol),
+		byQuote:        make(map[string][]Symbol),
+	}
+	for _, c := range info.Coins {
+		s.coins[strings.ToUpper(c.CurrencyCode)] = c
+	}
+	for _, b := range info.BaseCurrencies {
+		s.baseCurrencies[strings.ToUpper(b.CurrencyCode)] = b
+	}
+	for _, symbol := range info.Symbols {
+		s.byBase[symbol.Symbol.Base()] = append(s.byBase[symbol.Symbol.Base()], symbol)
+		s.byQuote[symbol.Symbol.Quote()] = append(s.byQuote[symbol.Symbol.Quote()], symbol)
+		if symbol.AllowTrading {
+			s.tradable = append(s.tradable, symbol)
+		}
+	}
+	return s
+}
+
+// diffSymbols returns the changes of the symbols from previous to current, in the order of their lists.
+func diffSymbols(previous *metadataSnapshot, current *metadataSnapshot) []MetadataChange {
+	var changes []MetadataChange
+	for _, symbol := range current.info.Symbols {
+		before, ok := previous.rules.symbol(symbol.Symbol)
+		switch {
+		case !ok:
+			changes = append(changes, MetadataChange{Kind: SymbolAdded, Symbol: symbol})
+		case before.AllowTrading && !symbol.AllowTrading:
+			changes = append(changes, MetadataChange{Kind: TradingDisabled, Symbol: symbol})
+		case !before.AllowTrading && symbol.AllowTrading:
+			changes = append(changes, MetadataChange{Kind: TradingEnabled, Symbol: symbol})
+		}
+	}
+	for _, symbol := range previous.info.Symbols {
+		if _, ok := current.rules.symbol(symbol.Symbol); !ok {
+			changes = append(changes, MetadataChange{Kind: SymbolRemoved, Symbol: symbol})
+		}
+	}
+	return changes
+}
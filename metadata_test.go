@@ -0,0 +1,354 @@
+package kryptono
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMetadataSource returns info, counting its calls. If release is set, calls wait for it.
+type fakeMetadataSource struct {
+	mu      sync.Mutex
+	info    *ExchangeInformationResp
+	err     error
+	calls   int32
+	release chan struct{}
+}
+
+func (s *fakeMetadataSource) ExchangeInformationContext(ctx context.Context) (*ExchangeInformationResp, error) {
+	atomic.AddInt32(&s.calls, 1)
+	s.mu.Lock()
+	release := s.release
+	s.mu.Unlock()
+	if release != nil {
+		<-release
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.info, s.err
+}
+
+func (s *fakeMetadataSource) set(info *ExchangeInformationResp, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.info, s.err = info, err
+}
+
+func TestMetadataCacheLookups(t *testing.T) {
+	info := testExchangeInformation()
+	info.Symbols = append(info.Symbols, Symbol{Symbol: "KNOW_ETH", AmountLimitDecimal: 2, PriceLimitDecimal: 8, AllowTrading: true})
+	cache := NewMetadataCache(&fakeMetadataSource{info: info}, time.Minute)
+	ctx := context.Background()
+
+	symbol, err := cache.Symbol(ctx, "know-btc")
+	assert.Nil(t, err)
+	assert.Equal(t, Market("KNOW_BTC"), symbol.Symbol)
+	_, err = cache.Symbol(ctx, "EOS_BTC")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	coin, err := cache.Coin(ctx, "know")
+	assert.Nil(t, err)
+	assert.Equal(t, "10", coin.MinimumOrderAmount.String())
+	_, err = cache.Coin(ctx, "EOS")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	base, err := cache.BaseCurrency(ctx, "BTC")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.001", base.MinimumTotalOrder.String())
+	_, err = cache.BaseCurrency(ctx, "EUR")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	know, err := cache.MarketsByBase(ctx, "KNOW")
+	assert.Nil(t, err)
+	assert.Len(t, know, 2)
+	btc, err := cache.MarketsByQuote(ctx, "btc")
+	assert.Nil(t, err)
+	assert.Len(t, btc, 2)
+	tradable, err := cache.TradableSymbols(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []Market{"KNOW_BTC", "KNOW_ETH"}, []Market{tradable[0].Symbol, tradable[1].Symbol})
+
+	// returned lists are copies
+	tradable[0].AllowTrading = false
+	tradable, _ = cache.TradableSymbols(ctx)
+	assert.True(t, tradable[0].AllowTrading)
+
+	validator, err := cache.OrderValidator(ctx)
+	assert.Nil(t, err)
+	small, _ := LimitOrder("KNOW_BTC").Buy().Price(MustParseDecimal("0.00001")).Size(NewDecimalFromInt(5)).Build()
+	assert.Len(t, validator.Validate(small), 2)
+	quantizer, err := cache.Quantizer(ctx)
+	assert.Nil(t, err)
+	minimum, err := quantizer.MinimumSize("KNOW_BTC", Decimal{})
+	assert.Nil(t, err)
+	assert.Equal(t, "10", minimum.String())
+}
+
+// testClock is a clock for MetadataCache that only moves when told to.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// waitForCalls waits until source has been called calls times.
+func waitForCalls(t *testing.T, source *fakeMetadataSource, calls int32) {
+	for i := 0; atomic.LoadInt32(&source.calls) < calls; i++ {
+		if i == 1000 {
+			t.Fatalf("source called %d times, expected %d", atomic.LoadInt32(&source.calls), calls)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitForIdle waits until no fetch of cache is running.
+func waitForIdle(t *testing.T, cache *MetadataCache) {
+	for i := 0; ; i++ {
+		cache.mu.Lock()
+		idle := cache.inflight == nil
+		cache.mu.Unlock()
+		if idle {
+			return
+		}
+		if i == 1000 {
+			t.Fatal("fetch didn't finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMetadataCacheTTL(t *testing.T) {
+	source := &fakeMetadataSource{info: testExchangeInformation()}
+	cache := NewMetadataCache(source, time.Minute)
+	clock := &testClock{now: time.Unix(1530683054, 0)}
+	cache.now = clock.Now
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.ExchangeInformation(ctx)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&source.calls))
+
+	// stale information is returned right away and refreshed in the background
+	next := testExchangeInformation()
+	next.Timezone = "CET"
+	source.set(next, nil)
+	release := make(chan struct{})
+	source.mu.Lock()
+	source.release = release
+	source.mu.Unlock()
+	clock.Add(time.Minute)
+	info, err := cache.ExchangeInformation(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "", info.Timezone)
+	waitForCalls(t, source, 2)
+	_, err = cache.ExchangeInformation(ctx)
+	assert.Nil(t, err)
+	close(release)
+	// joins the running refresh
+	assert.Nil(t, cache.Refresh(ctx))
+	info, err = cache.ExchangeInformation(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "CET", info.Timezone)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&source.calls))
+}
+
+func TestMetadataCacheBackoff(t *testing.T) {
+	source := &fakeMetadataSource{info: testExchangeInformation()}
+	cache := NewMetadataCache(source, time.Minute)
+	clock := &testClock{now: time.Unix(1530683054, 0)}
+	cache.now = clock.Now
+	ctx := context.Background()
+	assert.Nil(t, cache.Refresh(ctx))
+
+	// a failed refresh keeps the last information
+	source.set(nil, errors.New("unavailable"))
+	clock.Add(time.Minute)
+	_, err := cache.Symbol(ctx, "KNOW_BTC")
+	assert.Nil(t, err)
+	waitForCalls(t, source, 2)
+	waitForIdle(t, cache)
+	assert.NotNil(t, cache.Refresh(ctx))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&source.calls))
+
+	// no fetches during the backoff, which doubles after the second failure
+	for i := 0; i < 10; i++ {
+		_, err = cache.Symbol(ctx, "KNOW_BTC")
+		assert.Nil(t, err)
+	}
+	clock.Add(time.Second)
+	_, err = cache.Symbol(ctx, "KNOW_BTC")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&source.calls))
+	clock.Add(time.Second)
+	_, err = cache.Symbol(ctx, "KNOW_BTC")
+	assert.Nil(t, err)
+	waitForCalls(t, source, 4)
+	waitForIdle(t, cache)
+
+	source.set(testExchangeInformation(), nil)
+	assert.Nil(t, cache.Refresh(ctx))
+	assert.Equal(t, int32(5), atomic.LoadInt32(&source.calls))
+}
+
+func TestMetadataCacheFetchError(t *testing.T) {
+	source := &fakeMetadataSource{err: errors.New("unavailable")}
+	cache := NewMetadataCache(source, time.Minute)
+	clock := &testClock{now: time.Unix(1530683054, 0)}
+	cache.now = clock.Now
+
+	_, err := cache.TradableSymbols(context.Background())
+	assert.EqualError(t, err, "unavailable")
+	// the error is returned without another fetch during the backoff
+	_, err = cache.TradableSymbols(context.Background())
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&source.calls))
+
+	clock.Add(time.Second)
+	source.set(testExchangeInformation(), nil)
+	_, err = cache.TradableSymbols(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&source.calls))
+}
+
+func TestMetadataCacheSharesFetch(t *testing.T) {
+	source := &fakeMetadataSource{info: testExchangeInformation(), release: make(chan struct{})}
+	cache := NewMetadataCache(source, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.Symbol(context.Background(), "KNOW_BTC")
+			assert.Nil(t, err)
+		}()
+	}
+	// let all of them miss before the fetch returns
+	for atomic.LoadInt32(&source.calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(source.release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&source.calls))
+}
+
+func TestMetadataCacheWaitCanceled(t *testing.T) {
+	source := &fakeMetadataSource{info: testExchangeInformation(), release: make(chan struct{})}
+	cache := NewMetadataCache(source, time.Minute)
+	go cache.Refresh(context.Background())
+	for atomic.LoadInt32(&source.calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := cache.Symbol(ctx, "KNOW_BTC")
+	assert.True(t, errors.Is(err, context.Canceled))
+	close(source.release)
+}
+
+func TestMetadataCacheFetchOutlivesCaller(t *testing.T) {
+	source := &fakeMetadataSource{info: testExchangeInformation(), release: make(chan struct{})}
+	cache := NewMetadataCache(source, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	first := make(chan error)
+	go func() {
+		_, err := cache.Symbol(ctx, "KNOW_BTC")
+		first <- err
+	}()
+	for atomic.LoadInt32(&source.calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	second := make(chan error)
+	go func() {
+		_, err := cache.Symbol(context.Background(), "KNOW_BTC")
+		second <- err
+	}()
+
+	// the first caller gives up, the fetch goes on for the second
+	assert.True(t, errors.Is(<-first, context.DeadlineExceeded))
+	close(source.release)
+	assert.Nil(t, <-second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&source.calls))
+}
+
+func TestMetadataCacheChanges(t *testing.T) {
+	source := &fakeMetadataSource{info: testExchangeInformation()}
+	cache := NewMetadataCache(source, time.Minute)
+	var changes []MetadataChange
+	cache.OnChange(func(change MetadataChange) {
+		changes = append(changes, change)
+	})
+	ctx := context.Background()
+
+	assert.Nil(t, cache.Refresh(ctx))
+	assert.Empty(t, changes)
+
+	next := testExchangeInformation()
+	next.Symbols = []Symbol{
+		{Symbol: "KNOW_BTC", AmountLimitDecimal: 2, PriceLimitDecimal: 8, AllowTrading: false},
+		{Symbol: "GTO_BTC", AllowTrading: true},
+		{Symbol: "EOS_BTC", AllowTrading: true},
+	}
+	source.set(next, nil)
+	assert.Nil(t, cache.Refresh(ctx))
+	assert.Equal(t, []MetadataChange{
+		{Kind: TradingDisabled, Symbol: next.Symbols[0]},
+		{Kind: TradingEnabled, Symbol: next.Symbols[1]},
+		{Kind: SymbolAdded, Symbol: next.Symbols[2]},
+	}, changes)
+
+	changes = nil
+	source.set(&ExchangeInformationResp{Symbols: next.Symbols[:1]}, nil)
+	assert.Nil(t, cache.Refresh(ctx))
+	assert.Equal(t, []MetadataChange{
+		{Kind: SymbolRemoved, Symbol: next.Symbols[1]},
+		{Kind: SymbolRemoved, Symbol: next.Symbols[2]},
+	}, changes)
+}
+
+func TestMetadataCacheWithClient(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"symbols": [{"symbol": "GTO_ETH", "amount_limit_decimal": 0, "price_limit_decimal": 8, "allow_trading": true}]}`))
+	}))
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, "key", "secret")
+	assert.Nil(t, err)
+	cache := NewMetadataCache(client, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		symbol, err := cache.Symbol(context.Background(), "GTO-ETH")
+		assert.Nil(t, err)
+		assert.Equal(t, float64(8), symbol.PriceLimitDecimal)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
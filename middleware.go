@@ -0,0 +1,126 @@
+package kryptono
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Handler sends the signed request of a client method, named by endpoint (e.g. "NewOrder"), and
+// returns the exchange's response.
+type Handler func(endpoint string, request *http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler to observe or change requests and responses. A middleware is called
+// for every attempt of a request, including retries.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middlewares to the request pipeline. The first middleware is the outermost,
+// it sees the request first and the response last.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *client) error {
+		for _, m := range middlewares {
+			if m == nil {
+				return errors.New("middleware must not be nil")
+			}
+		}
+		c.middlewares = append(c.middlewares, middlewares...)
+		return nil
+	}
+}
+
+// Exchange is a finished request as reported by an Interceptor.
+type Exchange struct {
+	Endpoint string
+	// Request is the signed request, RequestBody its body.
+	Request     *http.Request
+	RequestBody []byte
+	// StatusCode, ResponseHeader and ResponseBody are empty if the request failed with Err.
+	// ResponseBody holds at most the first 64 KiB, ResponseTruncated tells whether there is more.
+	// Err is also set if the body couldn't be read, the response is passed on anyway.
+	StatusCode        int
+	ResponseHeader    http.Header
+	ResponseBody      []byte
+	ResponseTruncated bool
+	Duration          time.Duration
+	Err               error
+}
+
+// maximum number of bytes of a response body passed to an Interceptor
+const maxInterceptedBodySize = 64 << 10
+
+// Interceptor returns a middleware calling before ahead of every request and after once it has
+// finished. before may change the request, e.g. add headers; if it returns an error the request
+// isn't sent. Both functions may be nil. If after is set, the start of the response body is buffered to pass it on.
+func Interceptor(before func(endpoint string, request *http.Request) error, after func(exchange *Exchange)) Middleware {
+	return func(next Handler) Handler {
+		return func(endpoint string, request *http.Request) (*http.Response, error) {
+			if before != nil {
+				if err := before(endpoint, request); err != nil {
+					return nil, err
+				}
+			}
+			if after == nil {
+				return next(endpoint, request)
+			}
+
+			exchange := &Exchange{
+				Endpoint: endpoint,
+				Request:  request,
+			}
+			if request.GetBody != nil {
+				if body, err := request.GetBody(); err == nil {
+					exchange.RequestBody, _ = ioutil.ReadAll(body)
+					body.Close()
+				}
+			}
+
+			start := time.Now()
+			resp, err := next(endpoint, request)
+			if err != nil {
+				exchange.Duration = time.Since(start)
+				exchange.Err = err
+				after(exchange)
+				return nil, err
+			}
+			exchange.StatusCode = resp.StatusCode
+			exchange.ResponseHeader = resp.Header
+			// a body that can't be read is reported, the response is still passed on
+			exchange.ResponseBody, exchange.ResponseTruncated, exchange.Err = peekBody(resp, maxInterceptedBodySize)
+			exchange.Duration = time.Since(start)
+			after(exchange)
+			return resp, nil
+		}
+	}
+}
+
+// peekBody reads at most limit bytes of the body of resp and puts them back in front of the rest,
+// so resp is unchanged for the caller. truncated tells whether the body is longer.
+func peekBody(resp *http.Response, limit int64) (prefix []byte, truncated bool, err error) {
+	if resp.Body == nil {
+		return nil, false, nil
+	}
+	// one byte more tells whether the body is truncated
+	prefix, err = ioutil.ReadAll(io.LimitReader(resp.Body, limit+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+	if int64(len(prefix)) > limit {
+		return prefix[:limit], true, err
+	}
+	return prefix, false, err
+}
+
+// do sends the request through the middlewares.
+func (c *client) do(ep endpoint, request *http.Request) (*http.Response, error) {
+	h := c.logged(func(_ string, request *http.Request) (*http.Response, error) {
+		return c.http.Do(request)
+	})
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		h = c.middlewares[i](h)
+	}
+	return h(ep.name, request)
+}
@@ -0,0 +1,153 @@
+package kryptono
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"outer", "inner"}, r.Header["X-Trace"])
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"result": true}`))
+	}))
+	defer ts.Close()
+
+	var calls []string
+	tracing := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(endpoint string, request *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+endpoint)
+				request.Header.Add("X-Trace", name)
+				resp, err := next(endpoint, request)
+				calls = append(calls, name+" done")
+				return resp, err
+			}
+		}
+	}
+
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithMiddleware(tracing("outer"), tracing("inner")))
+	assert.Nil(t, err)
+
+	resp, err := client.Ping()
+	assert.Nil(t, err)
+	assert.True(t, resp.Result)
+	assert.Equal(t, []string{"outer Ping", "inner Ping", "inner done", "outer done"}, calls)
+}
+
+func TestInterceptor(t *testing.T) {
+	body := `{
+		"order_id" : "02140bef-0c98-4997-9412-9e7ca6f1cc0e",
+		"order_symbol" : "KNOW_ETH"
+	}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "4711", r.Header.Get("X-Request-Id"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	var exchanges []*Exchange
+	interceptor := Interceptor(func(endpoint string, request *http.Request) error {
+		request.Header.Set("X-Request-Id", "4711")
+		return nil
+	}, func(exchange *Exchange) {
+		exchanges = append(exchanges, exchange)
+	})
+
+	client, err := NewClient("key", "4a894c5c-8a7e-4337-bb6b-9fde16e3dddd", WithAccountAPIEndpoint(ts.URL), WithMiddleware(interceptor))
+	assert.Nil(t, err)
+
+	resp, err := client.CancelOrder(&CancelOrderRequest{
+		OrderID:     "02140bef-0c98-4997-9412-9e7ca6f1cc0e",
+		OrderSymbol: "KNOW_ETH",
+		Timestamp:   NewTimestampFromMillis(1429514463299),
+		RecvWindow:  5000,
+	})
+	assert.Nil(t, err)
+	// the response body is still available to the client
+	assert.Equal(t, Market("KNOW_ETH"), resp.OrderSymbol)
+
+	assert.Equal(t, 1, len(exchanges))
+	exchange := exchanges[0]
+	assert.Equal(t, "CancelOrder", exchange.Endpoint)
+	assert.Equal(t, "DELETE", exchange.Request.Method)
+	assert.Equal(t, "213235fefba2a1c791acff50ad0ad0322b8bfc1ef93efcc4fe1dd6a4504f700f", exchange.Request.Header.Get(HeaderSignature))
+	equal, err := isEqualJSON(`{
+		"order_id" : "02140bef-0c98-4997-9412-9e7ca6f1cc0e",
+		"order_symbol" : "KNOW_ETH",
+		"timestamp" : 1429514463299,
+		"recvWindow" : 5000
+	}`, string(exchange.RequestBody))
+	assert.Nil(t, err)
+	assert.True(t, equal, string(exchange.RequestBody))
+	assert.Equal(t, http.StatusOK, exchange.StatusCode)
+	assert.Equal(t, body, string(exchange.ResponseBody))
+	assert.True(t, exchange.Duration > 0)
+	assert.Nil(t, exchange.Err)
+}
+
+func TestInterceptorStopsRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not have been sent")
+	}))
+	defer ts.Close()
+
+	stop := errors.New("stop")
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithMiddleware(Interceptor(func(endpoint string, request *http.Request) error {
+		return stop
+	}, nil)))
+	assert.Nil(t, err)
+
+	_, err = client.Ping()
+	assert.True(t, errors.Is(err, stop), err)
+}
+
+func TestWithMiddlewareNil(t *testing.T) {
+	_, err := NewClient("key", "secret", WithMiddleware(nil))
+	assert.NotNil(t, err)
+}
+
+func TestInterceptorBoundsResponseBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"result": true, "padding": "` + strings.Repeat("a", 2*maxInterceptedBodySize) + `"}`))
+	}))
+	defer ts.Close()
+
+	var exchange *Exchange
+	interceptor := Interceptor(nil, func(e *Exchange) {
+		exchange = e
+	})
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithMiddleware(interceptor), WithMaxResponseSize(1024))
+	assert.Nil(t, err)
+
+	_, err = client.Ping()
+	assert.True(t, errors.Is(err, ErrResponseTooLarge), err)
+	assert.Equal(t, maxInterceptedBodySize, len(exchange.ResponseBody))
+	assert.True(t, exchange.ResponseTruncated)
+	assert.Nil(t, exchange.Err)
+}
+
+func TestInterceptorKeepsResponseOnReadError(t *testing.T) {
+	var exchange *Exchange
+	interceptor := Interceptor(nil, func(e *Exchange) {
+		exchange = e
+	})
+	body := &failingBody{}
+	handler := interceptor(func(endpoint string, request *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+	})
+
+	resp, err := handler("NewOrder", httptest.NewRequest(http.MethodPost, "http://localhost/api/v2/order/add", nil))
+	assert.Nil(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, http.StatusOK, exchange.StatusCode)
+	assert.EqualError(t, exchange.Err, "connection reset")
+	assert.False(t, body.closed)
+}
@@ -0,0 +1,121 @@
+package kryptono
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClientOption configures a client created with NewClient.
+type ClientOption func(*client) error
+
+// WithHTTPClient makes the client send its requests with httpClient instead of http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *client) error {
+		if httpClient == nil {
+			return errors.New("http client must not be nil")
+		}
+		c.http = httpClient
+		return nil
+	}
+}
+
+// WithTransport makes the client send its requests through transport. The configured
+// http.Client is copied, so a client passed with WithHTTPClient is not modified.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *client) error {
+		if transport == nil {
+			return errors.New("transport must not be nil")
+		}
+		c.transport = transport
+		return nil
+	}
+}
+
+// WithGeneralAPIEndpoint overrides the base URL of the general endpoints (ping, time, exchange info, market price).
+func WithGeneralAPIEndpoint(endpoint string) ClientOption {
+	return func(c *client) error {
+		u, err := validateEndpoint(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid general API endpoint, %v", err)
+		}
+		c.generalAPIEndpoint = u
+		return nil
+	}
+}
+
+// WithMarketAPIEndpoint overrides the base URL of the market endpoints (trade history, order book).
+func WithMarketAPIEndpoint(endpoint string) ClientOption {
+	return func(c *client) error {
+		u, err := validateEndpoint(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid market API endpoint, %v", err)
+		}
+		c.marketAPIEndpoint = u
+		return nil
+	}
+}
+
+// WithAccountAPIEndpoint overrides the base URL of the signed order and account endpoints.
+func WithAccountAPIEndpoint(endpoint string) ClientOption {
+	return func(c *client) error {
+		u, err := validateEndpoint(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid account API endpoint, %v", err)
+		}
+		c.accountAPIEndpoint = u
+		return nil
+	}
+}
+
+// WithMarketsAPIEndpoint overrides the base URL of the market summaries endpoint.
+func WithMarketsAPIEndpoint(endpoint string) ClientOption {
+	return func(c *client) error {
+		u, err := validateEndpoint(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid markets API endpoint, %v", err)
+		}
+		c.marketsAPIEndpoint = u
+		return nil
+	}
+}
+
+// WithTimeout sets a default timeout for every call, covering the whole request
+// including reading the response body. Deadlines set on a call's context still apply.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *client) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout must not be negative, got %v", timeout)
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *client) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+func validateEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("scheme of %q must be http or https", endpoint)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%q has no host", endpoint)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%q must not have a query or fragment", endpoint)
+	}
+	return strings.TrimRight(endpoint, "/"), nil
+}
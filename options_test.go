@@ -0,0 +1,92 @@
+package kryptono
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	assert.Nil(t, err)
+
+	impl := c.(*client)
+	assert.Equal(t, http.DefaultClient, impl.http)
+	assert.Equal(t, generalAPIEndpoint, impl.generalAPIEndpoint)
+	assert.Equal(t, marketAPIEndpoint, impl.marketAPIEndpoint)
+	assert.Equal(t, accountAPIEndpoint, impl.accountAPIEndpoint)
+	assert.Equal(t, marketsAPIEndpoint, impl.marketsAPIEndpoint)
+}
+
+func TestNewClientWithEndpoints(t *testing.T) {
+	c, err := NewClient("key", "secret",
+		WithGeneralAPIEndpoint("http://general.local/"),
+		WithMarketAPIEndpoint("http://market.local"),
+		WithAccountAPIEndpoint("https://account.local/k"),
+		WithMarketsAPIEndpoint("http://localhost:8080"),
+	)
+	assert.Nil(t, err)
+
+	impl := c.(*client)
+	assert.Equal(t, "http://general.local", impl.generalAPIEndpoint)
+	assert.Equal(t, "http://market.local", impl.marketAPIEndpoint)
+	assert.Equal(t, "https://account.local/k", impl.accountAPIEndpoint)
+	assert.Equal(t, "http://localhost:8080", impl.marketsAPIEndpoint)
+}
+
+func TestNewClientWithInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "general.local", "ftp://general.local", "http://", "http://general.local?a=b", "http://gen eral.local"} {
+		c, err := NewClient("key", "secret", WithGeneralAPIEndpoint(endpoint))
+		assert.Nil(t, c, endpoint)
+		assert.NotNil(t, err, endpoint)
+	}
+}
+
+func TestNewClientWithHTTPClientAndTimeout(t *testing.T) {
+	httpClient := &http.Client{}
+	transport := &countingTransport{}
+	c, err := NewClient("key", "secret", WithHTTPClient(httpClient), WithTransport(transport), WithTimeout(5*time.Second))
+	assert.Nil(t, err)
+
+	impl := c.(*client)
+	assert.Equal(t, 5*time.Second, impl.http.Timeout)
+	assert.Equal(t, transport, impl.http.Transport)
+	// the caller's client must stay untouched
+	assert.Equal(t, time.Duration(0), httpClient.Timeout)
+	assert.Nil(t, httpClient.Transport)
+
+	_, err = NewClient("key", "secret", WithHTTPClient(nil))
+	assert.NotNil(t, err)
+	_, err = NewClient("key", "secret", WithTimeout(-time.Second))
+	assert.NotNil(t, err)
+}
+
+func TestNewClientWithUserAgentAndTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "my-bot/1.0", r.Header.Get("User-Agent"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"result": true}`))
+	}))
+	defer ts.Close()
+
+	transport := &countingTransport{}
+	c, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithTransport(transport), WithUserAgent("my-bot/1.0"))
+	assert.Nil(t, err)
+
+	resp, err := c.Ping()
+	assert.Nil(t, err)
+	assert.True(t, resp.Result)
+	assert.Equal(t, 1, transport.requests)
+}
@@ -0,0 +1,53 @@
+package kryptono
+
+// number of decimal places of FillRatio
+const fillRatioScale = 8
+
+// Order is an order as returned by NewOrder, OrderDetail, OpenOrders, CompletedOrders and AllOrders.
+type Order struct {
+	OrderID     string      `json:"order_id"`
+	AccountID   string      `json:"account_id"`
+	OrderSymbol Market      `json:"order_symbol"`
+	OrderSide   OrderSide   `json:"order_side"`
+	Status      OrderStatus `json:"status"`
+	CreateTime  Timestamp   `json:"createTime"`
+	Type        OrderType   `json:"type"`
+	OrderPrice  Decimal     `json:"order_price"`
+	OrderSize   Decimal     `json:"order_size"`
+	Executed    Decimal     `json:"executed"`
+	StopPrice   Decimal     `json:"stop_price"`
+	Avg         Decimal     `json:"avg"`
+	Total       Amount      `json:"total"`
+}
+
+// Remaining returns the size not executed yet, never less than 0.
+func (o Order) Remaining() Decimal {
+	remaining := o.OrderSize.Sub(o.Executed)
+	if remaining.Sign() < 0 {
+		return Decimal{}
+	}
+	return remaining
+}
+
+// FillRatio returns the executed part of the size between 0 and 1, rounded to 8 decimal places.
+func (o Order) FillRatio() Decimal {
+	if o.OrderSize.Sign() <= 0 {
+		return Decimal{}
+	}
+	return o.Executed.Div(o.OrderSize, fillRatioScale)
+}
+
+// IsTerminal tells whether the order is filled or canceled and won't change anymore.
+func (o Order) IsTerminal() bool {
+	return o.Status == OrderStatusFilled || o.Status == OrderStatusCanceled
+}
+
+// Notional returns the value of the order in the quote currency, price times size. Orders without
+// a price, like market orders, are valued at their average execution price.
+func (o Order) Notional() Decimal {
+	price := o.OrderPrice
+	if price.IsZero() {
+		price = o.Avg
+	}
+	return price.Mul(o.OrderSize)
+}
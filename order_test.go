@@ -0,0 +1,48 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderHelpers(t *testing.T) {
+	order := Order{
+		Status:     OrderStatusPartiallyFilled,
+		Type:       OrderTypeLimit,
+		OrderPrice: MustParseDecimal("0.00001230"),
+		OrderSize:  NewDecimalFromInt(7777),
+		Executed:   NewDecimalFromInt(2000),
+	}
+	assert.Equal(t, "5777", order.Remaining().String())
+	assert.Equal(t, "0.25716857", order.FillRatio().String())
+	assert.Equal(t, "0.09565710", order.Notional().String())
+	assert.False(t, order.IsTerminal())
+
+	order.Status = OrderStatusFilled
+	order.Executed = MustParseDecimal("7777.5")
+	assert.True(t, order.IsTerminal())
+	assert.True(t, order.Remaining().IsZero())
+
+	order.Status = OrderStatusCanceled
+	assert.True(t, order.IsTerminal())
+
+	market := Order{Type: OrderTypeMarket, OrderSize: NewDecimalFromInt(10), Avg: MustParseDecimal("0.5")}
+	assert.Equal(t, "5.0", market.Notional().String())
+	assert.True(t, Order{}.FillRatio().IsZero())
+}
+
+func TestOrderTypesAreUnified(t *testing.T) {
+	body := []byte(`{"order_id": "1", "order_price": "0.1", "order_size": "2", "createTime": 1528277973947}`)
+
+	var detail OrderDetailResp
+	var all AllOrdersRespElement
+	assert.Nil(t, json.Unmarshal(body, &detail))
+	assert.Nil(t, json.Unmarshal(body, &all))
+
+	orders := []Order{detail, all, OpenOrdersRespElement(detail), CompletedOrdersRespElement(detail), NewOrderResp(detail)}
+	for _, o := range orders {
+		assert.Equal(t, "0.2", o.Notional().String())
+	}
+}
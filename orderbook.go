@@ -0,0 +1,173 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// number of decimal places of SpreadBps
+const spreadBpsScale = 2
+
+var basisPoints = NewDecimalFromInt(10000)
+
+// PriceLevel is a price level of an order book. It is encoded as [price, size].
+type PriceLevel struct {
+	Price Decimal
+	Size  Decimal
+}
+
+// MarshalJSON encodes the level as [price, size].
+func (l PriceLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]Decimal{l.Price, l.Size})
+}
+
+// UnmarshalJSON decodes a level from [price, size].
+func (l *PriceLevel) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	tmp := []Decimal{}
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	if len(tmp) != 2 {
+		return fmt.Errorf("expected price level of price and size, got %d values", len(tmp))
+	}
+	*l = PriceLevel{Price: tmp[0], Size: tmp[1]}
+	return nil
+}
+
+// BestBid returns the bid with the highest price, ok is false if there are no bids.
+func (b OrderBookResp) BestBid() (level PriceLevel, ok bool) {
+	for i, bid := range b.Bids {
+		if i == 0 || bid.Price.GreaterThan(level.Price) {
+			level = bid
+		}
+	}
+	return level, len(b.Bids) > 0
+}
+
+// BestAsk returns the ask with the lowest price, ok is false if there are no asks.
+func (b OrderBookResp) BestAsk() (level PriceLevel, ok bool) {
+	for i, ask := range b.Asks {
+		if i == 0 || ask.Price.LessThan(level.Price) {
+			level = ask
+		}
+	}
+	return level, len(b.Asks) > 0
+}
+
+// Spread returns best ask minus best bid, ok is false if a side is empty.
+func (b OrderBookResp) Spread() (Decimal, bool) {
+	bid, hasBid := b.BestBid()
+	ask, hasAsk := b.BestAsk()
+	if !hasBid || !hasAsk {
+		return Decimal{}, false
+	}
+	return ask.Price.Sub(bid.Price), true
+}
+
+// Mid returns the price between best bid and best ask, ok is false if a side is empty.
+func (b OrderBookResp) Mid() (Decimal, bool) {
+	bid, hasBid := b.BestBid()
+	ask, hasAsk := b.BestAsk()
+	if !hasBid || !hasAsk {
+		return Decimal{}, false
+	}
+	sum := bid.Price.Add(ask.Price)
+	// halving needs at most one more decimal place
+	return sum.Div(NewDecimalFromInt(2), sum.Scale()+1), true
+}
+
+// SpreadBps returns the spread in basis points of the mid price, rounded to 2 decimal places.
+// ok is false if a side is empty or the mid price isn't positive.
+func (b OrderBookResp) SpreadBps() (Decimal, bool) {
+	spread, ok := b.Spread()
+	if !ok {
+		return Decimal{}, false
+	}
+	mid, _ := b.Mid()
+	if mid.Sign() <= 0 {
+		return Decimal{}, false
+	}
+	return spread.Mul(basisPoints).Div(mid, spreadBpsScale), true
+}
+
+// AskDepthTo returns the total size of asks priced at or below price, what a buy up to price can take.
+func (b OrderBookResp) AskDepthTo(price Decimal) Decimal {
+	var depth Decimal
+	for _, ask := range b.Asks {
+		if ask.Price.Cmp(price) <= 0 {
+			depth = depth.Add(ask.Size)
+		}
+	}
+	return depth
+}
+
+// BidDepthTo returns the total size of bids priced at or above price, what a sell down to price can take.
+func (b OrderBookResp) BidDepthTo(price Decimal) Decimal {
+	var depth Decimal
+	for _, bid := range b.Bids {
+		if bid.Price.Cmp(price) >= 0 {
+			depth = depth.Add(bid.Size)
+		}
+	}
+	return depth
+}
+
+// AskSizeWithinBps returns the total size of asks priced at most bps basis points above the best ask.
+func (b OrderBookResp) AskSizeWithinBps(bps Decimal) Decimal {
+	best, ok := b.BestAsk()
+	if !ok {
+		return Decimal{}
+	}
+	// price <= best * (1 + bps/10000), without dividing
+	limit := best.Price.Mul(basisPoints.Add(bps))
+	var size Decimal
+	for _, ask := range b.Asks {
+		if ask.Price.Mul(basisPoints).Cmp(limit) <= 0 {
+			size = size.Add(ask.Size)
+		}
+	}
+	return size
+}
+
+// BidSizeWithinBps returns the total size of bids priced at most bps basis points below the best bid.
+func (b OrderBookResp) BidSizeWithinBps(bps Decimal) Decimal {
+	best, ok := b.BestBid()
+	if !ok {
+		return Decimal{}
+	}
+	// price >= best * (1 - bps/10000), without dividing
+	limit := best.Price.Mul(basisPoints.Sub(bps))
+	var size Decimal
+	for _, bid := range b.Bids {
+		if bid.Price.Mul(basisPoints).Cmp(limit) >= 0 {
+			size = size.Add(bid.Size)
+		}
+	}
+	return size
+}
+
+// IsCrossed tells whether the best bid is at or above the best ask, which a consistent book never is.
+func (b OrderBookResp) IsCrossed() bool {
+	bid, hasBid := b.BestBid()
+	ask, hasAsk := b.BestAsk()
+	return hasBid && hasAsk && bid.Price.Cmp(ask.Price) >= 0
+}
+
+// IsSorted tells whether the asks are in ascending and the bids in descending order of price,
+// without duplicate prices.
+func (b OrderBookResp) IsSorted() bool {
+	for i := 1; i < len(b.Asks); i++ {
+		if !b.Asks[i].Price.GreaterThan(b.Asks[i-1].Price) {
+			return false
+		}
+	}
+	for i := 1; i < len(b.Bids); i++ {
+		if !b.Bids[i].Price.LessThan(b.Bids[i-1].Price) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testOrderBook() OrderBookResp {
+	level := func(price string, size string) PriceLevel {
+		return PriceLevel{Price: MustParseDecimal(price), Size: MustParseDecimal(size)}
+	}
+	return OrderBookResp{
+		Asks: []PriceLevel{level("101", "1"), level("101.5", "2"), level("103", "4")},
+		Bids: []PriceLevel{level("99", "3"), level("98.9", "5"), level("97", "7")},
+	}
+}
+
+func TestOrderBookBestAndSpread(t *testing.T) {
+	book := testOrderBook()
+
+	bid, ok := book.BestBid()
+	assert.True(t, ok)
+	assert.Equal(t, "99", bid.Price.String())
+	ask, ok := book.BestAsk()
+	assert.True(t, ok)
+	assert.Equal(t, "101", ask.Price.String())
+
+	spread, ok := book.Spread()
+	assert.True(t, ok)
+	assert.Equal(t, "2", spread.String())
+	mid, ok := book.Mid()
+	assert.True(t, ok)
+	assert.Equal(t, "100.0", mid.String())
+	bps, ok := book.SpreadBps()
+	assert.True(t, ok)
+	assert.Equal(t, "200.00", bps.String())
+
+	empty := OrderBookResp{Asks: book.Asks}
+	_, ok = empty.BestBid()
+	assert.False(t, ok)
+	_, ok = empty.Spread()
+	assert.False(t, ok)
+	_, ok = empty.SpreadBps()
+	assert.False(t, ok)
+	assert.True(t, empty.BidSizeWithinBps(NewDecimalFromInt(10)).IsZero())
+}
+
+func TestOrderBookDepth(t *testing.T) {
+	book := testOrderBook()
+
+	assert.Equal(t, "3", book.AskDepthTo(MustParseDecimal("101.5")).String())
+	assert.Equal(t, "0", book.AskDepthTo(MustParseDecimal("100")).String())
+	assert.Equal(t, "8", book.BidDepthTo(MustParseDecimal("98.9")).String())
+
+	// 50 bps above 101 is 101.505, 150 bps below 99 is 97.515
+	assert.Equal(t, "3", book.AskSizeWithinBps(NewDecimalFromInt(50)).String())
+	assert.Equal(t, "1", book.AskSizeWithinBps(NewDecimalFromInt(0)).String())
+	assert.Equal(t, "8", book.BidSizeWithinBps(NewDecimalFromInt(150)).String())
+}
+
+func TestOrderBookConsistency(t *testing.T) {
+	book := testOrderBook()
+	assert.True(t, book.IsSorted())
+	assert.False(t, book.IsCrossed())
+
+	book.Bids[0].Price = MustParseDecimal("101")
+	assert.True(t, book.IsCrossed())
+	assert.True(t, book.IsSorted())
+
+	book = testOrderBook()
+	book.Asks[0], book.Asks[1] = book.Asks[1], book.Asks[0]
+	assert.False(t, book.IsSorted())
+	ask, _ := book.BestAsk()
+	assert.Equal(t, "101", ask.Price.String())
+}
+
+func TestPriceLevelJSON(t *testing.T) {
+	var level PriceLevel
+	assert.Nil(t, json.Unmarshal([]byte(`["0.00000035", "17790.00000000"]`), &level))
+	assert.Equal(t, "0.00000035", level.Price.String())
+	assert.Equal(t, "17790.00000000", level.Size.String())
+
+	output, err := json.Marshal(level)
+	assert.Nil(t, err)
+	assert.Equal(t, `["0.00000035","17790.00000000"]`, string(output))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`["0.00000035"]`), &level))
+}
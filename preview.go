@@ -0,0 +1,239 @@
+package kryptono
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// currency the discounted fee is paid in
+const knowCurrency = "KNOW"
+
+var percent = NewDecimalFromInt(100)
+
+// decimal places the average price of a market order has beyond the prices it filled at
+const averagePricePlaces = 4
+
+// FeeMode is the way trading fees are paid.
+type FeeMode string
+
+const (
+	// FeeStandard pays ExchangeFee.StandardFee in the quote currency of the market.
+	FeeStandard FeeMode = "standard"
+	// FeeKnow pays the discounted ExchangeFee.KnowFee in KNOW.
+	FeeKnow FeeMode = "know"
+)
+
+// BalanceChange is the effect of an order on the available balance of one currency.
+type BalanceChange struct {
+	Currency  string
+	Available Decimal
+	Change    Decimal
+	After     Decimal
+}
+
+// Sufficient tells whether the balance covers the change.
+func (c BalanceChange) Sufficient() bool {
+	return c.After.Sign() >= 0
+}
+
+// FeePreview is the cost of an order when fees are paid in one mode.
+type FeePreview struct {
+	Mode FeeMode
+	// Rate is the fee in percent.
+	Rate Decimal
+	// Fee is the value of the fee in the quote currency.
+	Fee Amount
+	// Charged is the fee in the currency it is paid in. It is invalid for FeeKnow if the
+	// market doesn't trade KNOW, as the fee can't be converted without a KNOW price.
+	Charged NullDecimal
+	// Net is the quote amount paid for a buy including fees, or received for a sell after fees.
+	Net      Amount
+	Balances []BalanceChange
+}
+
+// Sufficient tells whether all balances cover the order. A FeeKnow fee that couldn't be
+// converted isn't checked.
+func (p FeePreview) Sufficient() bool {
+	for _, balance := range p.Balances {
+		if !balance.Sufficient() {
+			return false
+		}
+	}
+	return true
+}
+
+// OrderPreview is the expected cost of an order before it is submitted.
+type OrderPreview struct {
+	Market Market
+	Side   OrderSide
+	Size   Decimal
+	// Price is the limit price, or the average price the order book fills a market order at.
+	Price Decimal
+	// Gross is price times size in the quote currency, without fees.
+	Gross    Amount
+	Standard FeePreview
+	Know     FeePreview
+}
+
+// OrderPreviewer previews orders with the fees from AccountInformation and the balances from
+// AccountBalances.
+type OrderPreviewer struct {
+	fee       ExchangeFee
+	available map[string]Decimal
+}
+
+// NewOrderPreviewer returns a previewer for fee and balances.
+func NewOrderPreviewer(fee ExchangeFee, balances AccountBalancesResp) *OrderPreviewer {
+	available := map[string]Decimal{}
+	for _, balance := range balances {
+		available[strings.ToUpper(balance.CurrencyCode)] = balance.Available
+	}
+	return &OrderPreviewer{fee: fee, available: available}
+}
+
+// Preview previews request. Limit and stop limit orders are priced at their limit price, market
+// orders are filled against book, which may be nil for the other types.
+func (p *OrderPreviewer) Preview(request *NewOrderRequest, book *OrderBookResp) (*OrderPreview, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	side := canonical(string(request.OrderSide), orderSides)
+	if canonical(string(request.Type), orderTypes) == OrderTypeMarket {
+		if book == nil {
+			return nil, fmt.Errorf("%w: market orders need an order book to preview", ErrInvalidParams)
+		}
+		if !book.Symbol.Equal(request.OrderSymbol) {
+			return nil, fmt.Errorf("%w: order book of %s doesn't match order symbol %s", ErrInvalidParams, book.Symbol.Symbol(), request.OrderSymbol.Symbol())
+		}
+		return p.PreviewMarket(*book, side, request.OrderSize)
+	}
+	gross := request.OrderSize.Mul(request.OrderPrice)
+	return p.preview(request.OrderSymbol, side, request.OrderSize, request.OrderPrice, gross), nil
+}
+
+// PreviewMarket previews a market order of size on side, filled level by level against book.
+// It fails if the book doesn't hold enough size.
+func (p *OrderPreviewer) PreviewMarket(book OrderBookResp, side OrderSide, size Decimal) (*OrderPreview, error) {
+	if !book.Symbol.IsValid() {
+		return nil, fmt.Errorf("%w: order book has no valid symbol", ErrInvalidParams)
+	}
+	if !side.IsValid() {
+		return nil, fmt.Errorf("%w: invalid order side %q", ErrInvalidParams, side)
+	}
+	side = canonical(string(side), orderSides)
+	if size.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: size must be positive", ErrInvalidParams)
+	}
+	levels := fillLevels(book, side)
+	gross, remaining, places := Decimal{}, size, int32(0)
+	for _, level := range levels {
+		if remaining.Sign() <= 0 {
+			break
+		}
+		filled := level.Size
+		if filled.GreaterThan(remaining) {
+			filled = remaining
+		}
+		gross = gross.Add(filled.Mul(level.Price))
+		if level.Price.places() > places {
+			places = level.Price.places()
+		}
+		remaining = remaining.Sub(filled)
+	}
+	if remaining.Sign() > 0 {
+		return nil, fmt.Errorf("%w: order book of %s only holds %s of %s", ErrInvalidParams, book.Symbol.Symbol(), size.Sub(remaining), size)
+	}
+	// the average price is rounded, the gross stays exact
+	price := trim(gross.Div(size, places+averagePricePlaces))
+	return p.preview(book.Symbol, side, size, price, gross), nil
+}
+
+func (p *OrderPreviewer) preview(market Market, side OrderSide, size Decimal, price Decimal, gross Decimal) *OrderPreview {
+	preview := &OrderPreview{Market: market, Side: side, Size: size, Price: price, Gross: NewAmount(trim(gross), market.Quote())}
+	preview.Standard = preview.feePreview(FeeStandard, p.fee.StandardFee)
+	preview.Standard.Balances = p.balances(preview, preview.Standard)
+	preview.Know = preview.feePreview(FeeKnow, p.fee.KnowFee)
+	preview.Know.Balances = p.balances(preview, preview.Know)
+	return preview
+}
+
+func (o *OrderPreview) feePreview(mode FeeMode, rate Decimal) FeePreview {
+	gross, quote := o.Gross.Value, o.Market.Quote()
+	fee := percentOf(gross, rate)
+	preview := FeePreview{Mode: mode, Rate: rate, Fee: NewAmount(fee, quote)}
+
+	net := gross
+	switch {
+	case mode == FeeStandard:
+		preview.Charged = NewNullDecimal(fee)
+		if o.Side == OrderSideBuy {
+			net = gross.Add(fee)
+		} else {
+			net = gross.Sub(fee)
+		}
+	case strings.EqualFold(quote, knowCurrency):
+		preview.Charged = NewNullDecimal(fee)
+	case strings.EqualFold(o.Market.Base(), knowCurrency):
+		// the fee is rate percent of the KNOW traded
+		preview.Charged = NewNullDecimal(percentOf(o.Size, rate))
+	}
+	preview.Net = NewAmount(net, quote)
+	return preview
+}
+
+// balances returns the balance changes of the order when fees are paid as in fee.
+func (p *OrderPreviewer) balances(order *OrderPreview, fee FeePreview) []BalanceChange {
+	base, quote := strings.ToUpper(order.Market.Base()), strings.ToUpper(order.Market.Quote())
+	changes := map[string]Decimal{}
+	if order.Side == OrderSideBuy {
+		changes[quote] = fee.Net.Value.Neg()
+		changes[base] = order.Size
+	} else {
+		changes[base] = order.Size.Neg()
+		changes[quote] = fee.Net.Value
+	}
+	if fee.Mode == FeeKnow && fee.Charged.Valid {
+		changes[knowCurrency] = changes[knowCurrency].Sub(fee.Charged.Decimal)
+	}
+
+	currencies := []string{base, quote}
+	if _, ok := changes[knowCurrency]; ok && base != knowCurrency && quote != knowCurrency {
+		currencies = append(currencies, knowCurrency)
+	}
+	balances := make([]BalanceChange, 0, len(currencies))
+	for _, currency := range currencies {
+		available := p.available[currency]
+		balances = append(balances, BalanceChange{
+			Currency:  currency,
+			Available: available,
+			Change:    changes[currency],
+			After:     available.Add(changes[currency]),
+		})
+	}
+	return balances
+}
+
+// fillLevels returns the levels a market order on side fills against, best price first.
+func fillLevels(book OrderBookResp, side OrderSide) []PriceLevel {
+	var levels []PriceLevel
+	if side == OrderSideBuy {
+		levels = append(levels, book.Asks...)
+		sort.SliceStable(levels, func(i, j int) bool { return levels[i].Price.LessThan(levels[j].Price) })
+	} else {
+		levels = append(levels, book.Bids...)
+		sort.SliceStable(levels, func(i, j int) bool { return levels[i].Price.GreaterThan(levels[j].Price) })
+	}
+	return levels
+}
+
+// percentOf returns rate percent of value, exactly.
+func percentOf(value Decimal, rate Decimal) Decimal {
+	product := value.Mul(rate)
+	return trim(product.Div(percent, product.Scale()+2))
+}
+
+// trim removes trailing zeros from d.
+func trim(d Decimal) Decimal {
+	return d.Round(d.places(), RoundDown)
+}
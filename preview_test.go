@@ -0,0 +1,157 @@
+package kryptono
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPreviewer() *OrderPreviewer {
+	return NewOrderPreviewer(ExchangeFee{
+		StandardFee: MustParseDecimal("0.1"),
+		KnowFee:     MustParseDecimal("0.05"),
+	}, AccountBalancesResp{
+		{CurrencyCode: "BTC", Available: MustParseDecimal("0.5")},
+		{CurrencyCode: "GTO", Available: MustParseDecimal("1000")},
+		{CurrencyCode: "KNOW", Available: MustParseDecimal("20")},
+	})
+}
+
+func testPreviewBook() OrderBookResp {
+	return OrderBookResp{
+		Symbol: "GTO_BTC",
+		Asks: []PriceLevel{
+			{Price: MustParseDecimal("0.0002"), Size: MustParseDecimal("100")},
+			{Price: MustParseDecimal("0.0001"), Size: MustParseDecimal("100")},
+		},
+		Bids: []PriceLevel{
+			{Price: MustParseDecimal("0.00008"), Size: MustParseDecimal("50")},
+			{Price: MustParseDecimal("0.00009"), Size: MustParseDecimal("50")},
+		},
+	}
+}
+
+func balanceStrings(balances []BalanceChange) map[string][3]string {
+	result := map[string][3]string{}
+	for _, balance := range balances {
+		result[balance.Currency] = [3]string{balance.Available.String(), balance.Change.String(), balance.After.String()}
+	}
+	return result
+}
+
+func TestPreviewLimitBuy(t *testing.T) {
+	request, err := LimitOrder("GTO-BTC").Buy().Price(MustParseDecimal("0.0001")).Size(NewDecimalFromInt(2000)).Build()
+	assert.Nil(t, err)
+
+	preview, err := testPreviewer().Preview(request, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.2 BTC", preview.Gross.String())
+
+	assert.Equal(t, FeeStandard, preview.Standard.Mode)
+	assert.Equal(t, "0.0002 BTC", preview.Standard.Fee.String())
+	assert.Equal(t, "0.0002", preview.Standard.Charged.Decimal.String())
+	assert.Equal(t, "0.2002 BTC", preview.Standard.Net.String())
+	assert.Equal(t, map[string][3]string{
+		"BTC": {"0.5", "-0.2002", "0.2998"},
+		"GTO": {"1000", "2000", "3000"},
+	}, balanceStrings(preview.Standard.Balances))
+	assert.True(t, preview.Standard.Sufficient())
+
+	// the KNOW fee can't be converted on a GTO market
+	assert.Equal(t, "0.0001 BTC", preview.Know.Fee.String())
+	assert.False(t, preview.Know.Charged.Valid)
+	assert.Equal(t, "0.2 BTC", preview.Know.Net.String())
+	assert.Len(t, preview.Know.Balances, 2)
+}
+
+func TestPreviewKnowMarket(t *testing.T) {
+	request, err := LimitOrder("KNOW_BTC").Sell().Price(MustParseDecimal("0.00001")).Size(NewDecimalFromInt(30)).Build()
+	assert.Nil(t, err)
+
+	preview, err := testPreviewer().Preview(request, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.0003 BTC", preview.Gross.String())
+	assert.Equal(t, "0.0002997 BTC", preview.Standard.Net.String())
+	assert.Equal(t, "0.015", preview.Know.Charged.Decimal.String())
+	assert.Equal(t, map[string][3]string{
+		"KNOW": {"20", "-30.015", "-10.015"},
+		"BTC":  {"0.5", "0.0003", "0.5003"},
+	}, balanceStrings(preview.Know.Balances))
+	assert.False(t, preview.Know.Sufficient())
+	assert.False(t, preview.Standard.Sufficient())
+}
+
+func TestPreviewMarket(t *testing.T) {
+	previewer := testPreviewer()
+
+	buy, err := previewer.PreviewMarket(testPreviewBook(), OrderSideBuy, NewDecimalFromInt(150))
+	assert.Nil(t, err)
+	// 100 at 0.0001 and 50 at 0.0002
+	assert.Equal(t, "0.02 BTC", buy.Gross.String())
+	assert.Equal(t, "0.00013333", buy.Price.String())
+	assert.Equal(t, "0.00002 BTC", buy.Standard.Fee.String())
+
+	sell, err := previewer.PreviewMarket(testPreviewBook(), OrderSideSell, NewDecimalFromInt(60))
+	assert.Nil(t, err)
+	// 50 at 0.00009 and 10 at 0.00008
+	assert.Equal(t, "0.0053 BTC", sell.Gross.String())
+	assert.Equal(t, "0.0052947 BTC", sell.Standard.Net.String())
+	assert.Equal(t, map[string][3]string{
+		"GTO": {"1000", "-60", "940"},
+		"BTC": {"0.5", "0.0052947", "0.5052947"},
+	}, balanceStrings(sell.Standard.Balances))
+
+	_, err = previewer.PreviewMarket(testPreviewBook(), OrderSideSell, NewDecimalFromInt(101))
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+	_, err = previewer.PreviewMarket(testPreviewBook(), OrderSide(""), NewDecimalFromInt(1))
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+	_, err = previewer.PreviewMarket(testPreviewBook(), OrderSideBuy, Decimal{})
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+}
+
+func TestPreviewMarketOrderRequest(t *testing.T) {
+	previewer := testPreviewer()
+	book := testPreviewBook()
+	request, err := MarketOrder("GTO_BTC").Buy().Size(NewDecimalFromInt(100)).Build()
+	assert.Nil(t, err)
+
+	preview, err := previewer.Preview(request, &book)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.01 BTC", preview.Gross.String())
+
+	_, err = previewer.Preview(request, nil)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+	book.Symbol = "KNOW_BTC"
+	_, err = previewer.Preview(request, &book)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+	_, err = previewer.Preview(&NewOrderRequest{}, nil)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+}
+
+func TestPreviewLowercase(t *testing.T) {
+	previewer := testPreviewer()
+	book := testPreviewBook()
+
+	market := &NewOrderRequest{OrderSymbol: "GTO_BTC", OrderSide: "buy", OrderSize: NewDecimalFromInt(100), Type: "market"}
+	preview, err := previewer.Preview(market, &book)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderSideBuy, preview.Side)
+	assert.Equal(t, "0.01 BTC", preview.Gross.String())
+	assert.Equal(t, "0.0001", preview.Price.String())
+
+	limit := &NewOrderRequest{OrderSymbol: "KNOW_BTC", OrderSide: "buy", OrderPrice: MustParseDecimal("0.00001"), OrderSize: NewDecimalFromInt(200), Type: "limit"}
+	preview, err = previewer.Preview(limit, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderSideBuy, preview.Side)
+	assert.Equal(t, "0.002002 BTC", preview.Standard.Net.String())
+	assert.Equal(t, map[string][3]string{
+		"KNOW": {"20", "199.9", "219.9"},
+		"BTC":  {"0.5", "-0.002", "0.498"},
+	}, balanceStrings(preview.Know.Balances))
+
+	preview, err = previewer.PreviewMarket(book, "sell", NewDecimalFromInt(50))
+	assert.Nil(t, err)
+	assert.Equal(t, OrderSideSell, preview.Side)
+	assert.Equal(t, "0.0045 BTC", preview.Gross.String())
+}
@@ -0,0 +1,159 @@
+package kryptono
+
+import "fmt"
+
+// Adjustment reports how a value was changed by quantization.
+type Adjustment struct {
+	Original Decimal
+	Value    Decimal
+	// Delta is Value - Original
+	Delta Decimal
+}
+
+func newAdjustment(original Decimal, value Decimal) Adjustment {
+	return Adjustment{Original: original, Value: value, Delta: value.Sub(original)}
+}
+
+// Changed tells whether the value was changed.
+func (a Adjustment) Changed() bool {
+	return !a.Delta.IsZero()
+}
+
+// OrderAdjustments reports how the values of an order were changed by quantization.
+type OrderAdjustments struct {
+	Price     Adjustment
+	StopPrice Adjustment
+	Size      Adjustment
+}
+
+// Changed tells whether any value of the order was changed.
+func (a OrderAdjustments) Changed() bool {
+	return a.Price.Changed() || a.StopPrice.Changed() || a.Size.Changed()
+}
+
+// Quantizer rounds prices and sizes to the precision each market allows and snaps sizes to the
+// minimum valid size, following the rules from ExchangeInformation.
+type Quantizer struct {
+	rules *exchangeRules
+}
+
+// NewQuantizer returns a quantizer for the rules in info.
+func NewQuantizer(info *ExchangeInformationResp) *Quantizer {
+	return &Quantizer{rules: newExchangeRules(info)}
+}
+
+// Price rounds price of an order on side to the precision of market. The touch modes round
+// depending on side, the other modes ignore it.
+func (q *Quantizer) Price(market Market, side OrderSide, price Decimal, mode RoundingMode) (Adjustment, error) {
+	symbol, err := q.symbol(market)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	mode, err = modeForSide(mode, side)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	rounded := price.Round(int32(symbol.PriceLimitDecimal), mode)
+	if price.Sign() > 0 && rounded.Sign() <= 0 {
+		return Adjustment{}, fmt.Errorf("%w: price %s rounds to 0 in %s", ErrInvalidParams, price, market.Symbol())
+	}
+	return newAdjustment(price, rounded), nil
+}
+
+// Size rounds size to the precision of market. Sizes have no touch, the touch modes fail.
+func (q *Quantizer) Size(market Market, size Decimal, mode RoundingMode) (Adjustment, error) {
+	symbol, err := q.symbol(market)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	if mode == RoundTowardTouch || mode == RoundAwayFromTouch {
+		return Adjustment{}, fmt.Errorf("%w: rounding mode %s is only for prices", ErrInvalidParams, mode)
+	}
+	return newAdjustment(size, size.Round(int32(symbol.AmountLimitDecimal), mode)), nil
+}
+
+// MinimumSize returns the smallest size of an order in market at price satisfying both the minimum
+// order amount and the minimum total, at the precision of market. For a zero price, like of a market
+// order, only the minimum order amount is considered.
+func (q *Quantizer) MinimumSize(market Market, price Decimal) (Decimal, error) {
+	symbol, err := q.symbol(market)
+	if err != nil {
+		return Decimal{}, err
+	}
+	places := int32(symbol.AmountLimitDecimal)
+
+	minimum := q.rules.minimumSizes[market.Base()]
+	if notional, ok := q.rules.minimumNotionals[market.Quote()]; ok && price.Sign() > 0 {
+		if size := notional.DivRound(price, places, RoundUp); size.GreaterThan(minimum) {
+			minimum = size
+		}
+	}
+	return minimum.Round(places, RoundUp), nil
+}
+
+// SnapSize rounds size to the precision of market with mode and raises it to the minimum size at
+// price if it is below.
+func (q *Quantizer) SnapSize(market Market, price Decimal, size Decimal, mode RoundingMode) (Adjustment, error) {
+	adjustment, err := q.Size(market, size, mode)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	minimum, err := q.MinimumSize(market, price)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	if adjustment.Value.LessThan(minimum) {
+		return newAdjustment(size, minimum), nil
+	}
+	return adjustment, nil
+}
+
+// Order returns a copy of request with its prices rounded with priceMode and its size snapped
+// with sizeMode, and how they were changed.
+func (q *Quantizer) Order(request *NewOrderRequest, priceMode RoundingMode, sizeMode RoundingMode) (*NewOrderRequest, OrderAdjustments, error) {
+	quantized := *request
+	var adjustments OrderAdjustments
+	var err error
+
+	if !request.OrderPrice.IsZero() {
+		if adjustments.Price, err = q.Price(request.OrderSymbol, request.OrderSide, request.OrderPrice, priceMode); err != nil {
+			return nil, OrderAdjustments{}, err
+		}
+		quantized.OrderPrice = adjustments.Price.Value
+	}
+	if request.StopPrice != nil {
+		if adjustments.StopPrice, err = q.Price(request.OrderSymbol, request.OrderSide, *request.StopPrice, priceMode); err != nil {
+			return nil, OrderAdjustments{}, err
+		}
+		quantized.StopPrice = &adjustments.StopPrice.Value
+	}
+	if adjustments.Size, err = q.SnapSize(request.OrderSymbol, quantized.OrderPrice, request.OrderSize, sizeMode); err != nil {
+		return nil, OrderAdjustments{}, err
+	}
+	quantized.OrderSize = adjustments.Size.Value
+	return &quantized, adjustments, nil
+}
+
+func (q *Quantizer) symbol(market Market) (Symbol, error) {
+	symbol, ok := q.rules.symbol(market)
+	if !ok {
+		return Symbol{}, fmt.Errorf("%w: market %s is not listed", ErrInvalidParams, market)
+	}
+	return symbol, nil
+}
+
+// modeForSide resolves the touch modes to rounding up or down for side.
+func modeForSide(mode RoundingMode, side OrderSide) (RoundingMode, error) {
+	if mode != RoundTowardTouch && mode != RoundAwayFromTouch {
+		return mode, nil
+	}
+	buy := canonical(string(side), orderSides) == OrderSideBuy
+	if !buy && canonical(string(side), orderSides) != OrderSideSell {
+		return mode, fmt.Errorf("%w: rounding mode %s needs a side, got %q", ErrInvalidParams, mode, side)
+	}
+	// the touch of a buy is above its price, of a sell below
+	if buy == (mode == RoundTowardTouch) {
+		return RoundUp, nil
+	}
+	return RoundDown, nil
+}
@@ -0,0 +1,117 @@
+package kryptono
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantizerPrice(t *testing.T) {
+	q := NewQuantizer(testExchangeInformation())
+	price := MustParseDecimal("0.000012345")
+
+	for _, c := range []struct {
+		side     OrderSide
+		mode     RoundingMode
+		expected string
+	}{
+		{OrderSideBuy, RoundDown, "0.00001234"},
+		{OrderSideBuy, RoundUp, "0.00001235"},
+		{OrderSideBuy, RoundHalfEven, "0.00001234"},
+		{OrderSideBuy, RoundTowardTouch, "0.00001235"},
+		{OrderSideBuy, RoundAwayFromTouch, "0.00001234"},
+		{OrderSideSell, RoundTowardTouch, "0.00001234"},
+		{"sell", RoundAwayFromTouch, "0.00001235"},
+	} {
+		adjustment, err := q.Price("KNOW_BTC", c.side, price, c.mode)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, adjustment.Value.String(), c)
+		assert.True(t, adjustment.Changed())
+		assert.Equal(t, "0.000012345", adjustment.Original.String())
+	}
+
+	adjustment, err := q.Price("KNOW_BTC", OrderSideBuy, MustParseDecimal("0.00001234"), RoundUp)
+	assert.Nil(t, err)
+	assert.False(t, adjustment.Changed())
+
+	adjustment, err = q.Price("know-btc", OrderSideBuy, price, RoundUp)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.000000005", adjustment.Delta.String())
+
+	_, err = q.Price("KNOW_BTC", "", price, RoundTowardTouch)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+	_, err = q.Price("KNOW_BTC", OrderSideBuy, MustParseDecimal("0.000000001"), RoundDown)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+	_, err = q.Price("EOS_BTC", OrderSideBuy, price, RoundDown)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+}
+
+func TestQuantizerSize(t *testing.T) {
+	q := NewQuantizer(testExchangeInformation())
+
+	adjustment, err := q.Size("KNOW_BTC", MustParseDecimal("100.555"), RoundHalfEven)
+	assert.Nil(t, err)
+	assert.Equal(t, "100.56", adjustment.Value.String())
+	assert.Equal(t, "0.005", adjustment.Delta.String())
+
+	adjustment, err = q.Size("GTO_BTC", MustParseDecimal("1.9"), RoundDown)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", adjustment.Value.String())
+
+	_, err = q.Size("KNOW_BTC", NewDecimalFromInt(1), RoundTowardTouch)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+}
+
+func TestQuantizerMinimumSize(t *testing.T) {
+	q := NewQuantizer(testExchangeInformation())
+
+	// 0.001 BTC / 0.00001230 = 81.30081..., rounded up to 2 places
+	minimum, err := q.MinimumSize("KNOW_BTC", MustParseDecimal("0.00001230"))
+	assert.Nil(t, err)
+	assert.Equal(t, "81.31", minimum.String())
+
+	// at a high price the minimum amount of KNOW is larger
+	minimum, err = q.MinimumSize("KNOW_BTC", MustParseDecimal("0.001"))
+	assert.Nil(t, err)
+	assert.Equal(t, "10", minimum.String())
+
+	minimum, err = q.MinimumSize("KNOW_BTC", Decimal{})
+	assert.Nil(t, err)
+	assert.Equal(t, "10", minimum.String())
+
+	adjustment, err := q.SnapSize("KNOW_BTC", MustParseDecimal("0.00001230"), MustParseDecimal("5"), RoundDown)
+	assert.Nil(t, err)
+	assert.Equal(t, "81.31", adjustment.Value.String())
+	assert.Equal(t, "76.31", adjustment.Delta.String())
+
+	adjustment, err = q.SnapSize("KNOW_BTC", MustParseDecimal("0.00001230"), MustParseDecimal("100.009"), RoundDown)
+	assert.Nil(t, err)
+	assert.Equal(t, "100.00", adjustment.Value.String())
+}
+
+func TestQuantizerOrder(t *testing.T) {
+	q := NewQuantizer(testExchangeInformation())
+	stop := MustParseDecimal("0.000012001")
+	request := &NewOrderRequest{
+		OrderSymbol: "KNOW_BTC",
+		OrderSide:   OrderSideSell,
+		Type:        OrderTypeStopLimit,
+		OrderPrice:  MustParseDecimal("0.000011999"),
+		StopPrice:   &stop,
+		OrderSize:   MustParseDecimal("90.123"),
+	}
+
+	quantized, adjustments, err := q.Order(request, RoundAwayFromTouch, RoundDown)
+	assert.Nil(t, err)
+	assert.True(t, adjustments.Changed())
+	assert.Equal(t, "0.00001200", quantized.OrderPrice.String())
+	assert.Equal(t, "0.00001201", quantized.StopPrice.String())
+	assert.Equal(t, "90.12", quantized.OrderSize.String())
+	assert.Equal(t, "0.000000001", adjustments.Price.Delta.String())
+	// the request is unchanged
+	assert.Equal(t, "0.000011999", request.OrderPrice.String())
+	assert.Equal(t, "0.000012001", request.StopPrice.String())
+
+	assert.Nil(t, NewOrderValidator(testExchangeInformation()).Validate(quantized))
+}
@@ -0,0 +1,201 @@
+package kryptono
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitMode decides what a RateLimiter does with a call that would exceed a limit.
+type RateLimitMode int
+
+const (
+	// RateLimitBlock makes calls wait until the budget allows them.
+	RateLimitBlock RateLimitMode = iota
+	// RateLimitReject fails calls with ErrRateLimitExceeded instead of waiting.
+	RateLimitReject
+)
+
+const (
+	// RateLimitTypeRequests limits the weight of all requests.
+	RateLimitTypeRequests = "REQUESTS"
+	// RateLimitTypeOrders limits the number of placed orders.
+	RateLimitTypeOrders = "ORDERS"
+)
+
+// ErrRateLimitExceeded is returned by a rejecting RateLimiter for calls over budget. It matches ErrRateLimited.
+var ErrRateLimitExceeded = fmt.Errorf("client side limit exceeded, %w", ErrRateLimited)
+
+// RateLimitUsage is the current usage of a single rate limit.
+type RateLimitUsage struct {
+	Type     string
+	Interval string
+	Limit    int
+	Used     int
+	// ResetAt is when the current window ends and Used drops back to 0.
+	ResetAt time.Time
+}
+
+// RateLimiter keeps calls within the exchange's rate limits as published by ExchangeInformation.
+// Limits are counted in fixed windows aligned to their interval. A RateLimiter is safe for
+// concurrent use and can be shared by several clients using the same API key.
+type RateLimiter struct {
+	mu      sync.Mutex
+	mode    RateLimitMode
+	windows []*rateWindow
+	now     func() time.Time
+}
+
+type rateWindow struct {
+	limit    RateLimit
+	interval time.Duration
+	start    time.Time
+	used     int
+}
+
+// NewRateLimiter creates a limiter for limits. Limits can be loaded later with Update, a client
+// configured with WithRateLimiter also updates them from every ExchangeInformation response.
+func NewRateLimiter(limits []RateLimit, mode RateLimitMode) *RateLimiter {
+	l := &RateLimiter{
+		mode: mode,
+		now:  time.Now,
+	}
+	l.Update(limits)
+	return l
+}
+
+// WithRateLimiter makes the client wait for, or reject, calls that would exceed the limiter's budget.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *client) error {
+		if limiter == nil {
+			return errors.New("rate limiter must not be nil")
+		}
+		c.limiter = limiter
+		return nil
+	}
+}
+
+// Update replaces the limits. Usage of limits that didn't change is kept.
+func (l *RateLimiter) Update(limits []RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	windows := make([]*rateWindow, 0, len(limits))
+	for _, limit := range limits {
+		interval, ok := rateLimitInterval(limit.Interval)
+		if !ok || limit.Limit <= 0 {
+			continue
+		}
+		w := &rateWindow{limit: limit, interval: interval}
+		for _, old := range l.windows {
+			if strings.EqualFold(old.limit.Type, limit.Type) && old.interval == interval {
+				w.start, w.used = old.start, old.used
+			}
+		}
+		windows = append(windows, w)
+	}
+	l.windows = windows
+}
+
+// Usage returns the current usage of every limit.
+func (l *RateLimiter) Usage() []RateLimitUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	usage := make([]RateLimitUsage, 0, len(l.windows))
+	for _, w := range l.windows {
+		w.advance(now)
+		usage = append(usage, RateLimitUsage{
+			Type:     w.limit.Type,
+			Interval: w.limit.Interval,
+			Limit:    w.limit.Limit,
+			Used:     w.used,
+			ResetAt:  w.start.Add(w.interval),
+		})
+	}
+	return usage
+}
+
+// acquire takes the endpoint's weight from all limits, waiting for the budget if necessary.
+func (l *RateLimiter) acquire(ctx context.Context, ep endpoint) error {
+	for {
+		wait, err := l.tryAcquire(ep)
+		if err != nil || wait == 0 {
+			return err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// tryAcquire takes the endpoint's weight if all limits allow it, otherwise it returns how long to wait.
+func (l *RateLimiter) tryAcquire(ep endpoint) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	for _, w := range l.windows {
+		w.advance(now)
+		cost := w.cost(ep)
+		if cost > w.limit.Limit {
+			return 0, fmt.Errorf("%w: %s costs %d of %s per %s, limit is %d", ErrRateLimitExceeded, ep.name, cost, w.limit.Type, w.limit.Interval, w.limit.Limit)
+		}
+		if w.used+cost > w.limit.Limit {
+			if l.mode == RateLimitReject {
+				return 0, fmt.Errorf("%w: %d of %d %s per %s used", ErrRateLimitExceeded, w.used, w.limit.Limit, w.limit.Type, w.limit.Interval)
+			}
+			if reset := w.start.Add(w.interval).Sub(now); reset > wait {
+				wait = reset
+			}
+		}
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+	for _, w := range l.windows {
+		w.used += w.cost(ep)
+	}
+	return 0, nil
+}
+
+// advance starts a new window if the current one has ended.
+func (w *rateWindow) advance(now time.Time) {
+	start := now.Truncate(w.interval)
+	if !start.Equal(w.start) {
+		w.start = start
+		w.used = 0
+	}
+}
+
+func (w *rateWindow) cost(ep endpoint) int {
+	switch strings.ToUpper(w.limit.Type) {
+	case RateLimitTypeOrders:
+		if ep.placesOrder {
+			return 1
+		}
+		return 0
+	default:
+		return ep.weight
+	}
+}
+
+func rateLimitInterval(interval string) (time.Duration, bool) {
+	switch strings.ToUpper(interval) {
+	case "SECOND":
+		return time.Second, true
+	case "MINUTE":
+		return time.Minute, true
+	case "HOUR":
+		return time.Hour, true
+	case "DAY":
+		return 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,139 @@
+package kryptono
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimiter(limits []RateLimit, mode RateLimitMode, now *time.Time) *RateLimiter {
+	l := NewRateLimiter(limits, mode)
+	l.now = func() time.Time {
+		return *now
+	}
+	return l
+}
+
+func TestRateLimiterReject(t *testing.T) {
+	now := time.Date(2019, 11, 24, 11, 6, 16, 0, time.UTC)
+	l := newTestRateLimiter([]RateLimit{{Type: "REQUESTS", Interval: "MINUTE", Limit: 7}}, RateLimitReject, &now)
+
+	assert.Nil(t, l.acquire(context.Background(), endpointOrderBook))
+	assert.Nil(t, l.acquire(context.Background(), endpointPing))
+	assert.Nil(t, l.acquire(context.Background(), endpointPing))
+	err := l.acquire(context.Background(), endpointPing)
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded), err)
+
+	usage := l.Usage()
+	assert.Equal(t, 1, len(usage))
+	assert.Equal(t, "REQUESTS", usage[0].Type)
+	assert.Equal(t, 7, usage[0].Used)
+	assert.Equal(t, time.Date(2019, 11, 24, 11, 7, 0, 0, time.UTC), usage[0].ResetAt)
+
+	// a new window starts with the next minute
+	now = now.Add(time.Minute)
+	assert.Nil(t, l.acquire(context.Background(), endpointPing))
+	assert.Equal(t, 1, l.Usage()[0].Used)
+}
+
+func TestRateLimiterOrders(t *testing.T) {
+	now := time.Date(2019, 11, 24, 11, 6, 16, 0, time.UTC)
+	l := newTestRateLimiter([]RateLimit{
+		{Type: "REQUESTS", Interval: "MINUTE", Limit: 100},
+		{Type: "ORDERS", Interval: "SECOND", Limit: 1},
+	}, RateLimitReject, &now)
+
+	assert.Nil(t, l.acquire(context.Background(), endpointNewOrder))
+	// reading doesn't count as order
+	assert.Nil(t, l.acquire(context.Background(), endpointOpenOrders))
+	assert.True(t, errors.Is(l.acquire(context.Background(), endpointNewOrder), ErrRateLimitExceeded))
+
+	usage := l.Usage()
+	assert.Equal(t, 6, usage[0].Used)
+	assert.Equal(t, 1, usage[1].Used)
+}
+
+func TestRateLimiterWeightOverLimit(t *testing.T) {
+	l := NewRateLimiter([]RateLimit{{Type: "REQUESTS", Interval: "SECOND", Limit: 2}}, RateLimitBlock)
+	err := l.acquire(context.Background(), endpointOrderBook)
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded), err)
+}
+
+func TestRateLimiterBlocks(t *testing.T) {
+	l := NewRateLimiter([]RateLimit{{Type: "REQUESTS", Interval: "SECOND", Limit: 1}}, RateLimitBlock)
+	assert.Nil(t, l.acquire(context.Background(), endpointPing))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	// unless the window just ended, the second call has to wait for the next second
+	err := l.acquire(ctx, endpointPing)
+	if err != nil {
+		assert.Equal(t, context.DeadlineExceeded, err)
+	}
+
+	assert.Nil(t, l.acquire(context.Background(), endpointPing))
+}
+
+func TestRateLimiterUpdateKeepsUsage(t *testing.T) {
+	now := time.Date(2019, 11, 24, 11, 6, 16, 0, time.UTC)
+	l := newTestRateLimiter([]RateLimit{{Type: "REQUESTS", Interval: "MINUTE", Limit: 10}}, RateLimitReject, &now)
+	assert.Nil(t, l.acquire(context.Background(), endpointPing))
+
+	l.Update([]RateLimit{
+		{Type: "REQUESTS", Interval: "MINUTE", Limit: 20},
+		{Type: "REQUESTS", Interval: "FORTNIGHT", Limit: 20},
+	})
+	usage := l.Usage()
+	assert.Equal(t, 1, len(usage))
+	assert.Equal(t, 20, usage[0].Limit)
+	assert.Equal(t, 1, usage[0].Used)
+}
+
+func TestRateLimiterConcurrentUse(t *testing.T) {
+	l := NewRateLimiter([]RateLimit{{Type: "REQUESTS", Interval: "DAY", Limit: 50}}, RateLimitReject)
+
+	var allowed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if l.acquire(context.Background(), endpointPing) == nil {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(50), allowed)
+}
+
+func TestClientLoadsRateLimitsFromExchangeInformation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"rate_limits": [{"type": "REQUESTS", "interval": "DAY", "limit": 6}]}`))
+	}))
+	defer ts.Close()
+
+	l := NewRateLimiter(nil, RateLimitReject)
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithRateLimiter(l))
+	assert.Nil(t, err)
+
+	_, err = client.ExchangeInformation()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, l.Usage()[0].Used)
+
+	_, err = client.Ping()
+	assert.Nil(t, err)
+	_, err = client.ExchangeInformation()
+	assert.Nil(t, err)
+	_, err = client.Ping()
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded), err)
+	assert.Equal(t, 6, l.Usage()[0].Used)
+}
@@ -0,0 +1,212 @@
+package kryptono
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Read-only endpoints are retried on
+// transport errors and on the status codes in RetryableStatus. NewOrder and CancelOrder are only
+// retried when the request provably never reached the server, when the server answered with
+// 429 Too Many Requests, or after the outcome of the failed attempt has been confirmed through
+// OpenOrders, CompletedOrders or OrderDetail.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts. A Retry-After header asking for a longer wait ends retrying.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every attempt.
+	Multiplier float64
+	// Jitter randomizes every wait by up to this fraction, e.g. 0.2 for +/-20%.
+	Jitter float64
+	// RetryableStatus are the HTTP status codes retried for read-only endpoints.
+	RetryableStatus []int
+}
+
+// DefaultRetryPolicy returns a policy with 4 attempts and exponential backoff starting at 200ms.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy enables retries of failed requests. Without this option every request is sent once.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *client) error {
+		c.retry = &policy
+		return nil
+	}
+}
+
+// ErrOutcomeUnknown is matched by errors of NewOrder and CancelOrder calls that failed in a way
+// that leaves it open whether the exchange executed them.
+var ErrOutcomeUnknown = errors.New("outcome of request unknown")
+
+// outcomeUnknownError is returned for a request that may or may not have been executed by the server.
+type outcomeUnknownError struct {
+	err error
+}
+
+func (e *outcomeUnknownError) Error() string {
+	return e.err.Error()
+}
+
+func (e *outcomeUnknownError) Unwrap() error {
+	return e.err
+}
+
+func (e *outcomeUnknownError) Is(target error) bool {
+	return target == ErrOutcomeUnknown
+}
+
+// isOutcomeUnknown tells whether a request to a non-idempotent endpoint may have been executed even though it failed.
+func isOutcomeUnknown(resp *response, err error) bool {
+	if _, ok := err.(*outcomeUnknownError); ok {
+		return true
+	}
+	return resp != nil && resp.StatusCode >= http.StatusInternalServerError
+}
+
+// sendUnconfirmed sends a request to a non-idempotent endpoint. When an attempt fails with an unknown
+// outcome, confirm is asked whether the request has been executed. If it has, confirmed is reported
+// and the request is not sent again, if it provably hasn't, the request is retried.
+func (c *client) sendUnconfirmed(ctx context.Context, send func() (*response, error), confirm func() (executed bool, err error)) (*response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+		if !isOutcomeUnknown(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			err = &outcomeUnknownError{err: checkHTTPStatus(*resp, http.StatusOK)}
+			drainAndClose(resp.Body)
+		}
+		if !c.retry.enabled() || attempt >= c.retry.MaxAttempts {
+			return nil, err
+		}
+
+		if sleepErr := sleep(ctx, c.retry.backoff(attempt)); sleepErr != nil {
+			// the failed attempt may still have been executed
+			return nil, &outcomeUnknownError{err: sleepErr}
+		}
+		// if the outcome can't be confirmed it stays unknown
+		executed, confirmErr := confirm()
+		if errors.Is(confirmErr, ErrOutcomeUnknown) {
+			return nil, confirmErr
+		}
+		if confirmErr != nil {
+			return nil, err
+		}
+		if executed {
+			return nil, nil
+		}
+	}
+}
+
+func (p *RetryPolicy) enabled() bool {
+	return p != nil && p.MaxAttempts > 1
+}
+
+// shouldRetry decides whether the given attempt is retried and how long to wait before the next one.
+func (p *RetryPolicy) shouldRetry(ep endpoint, attempt int, resp *response, sent bool, err error) (time.Duration, bool) {
+	if !p.enabled() || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if err != nil {
+		if ep.idempotent || !sent {
+			return p.backoff(attempt), true
+		}
+		return 0, false
+	}
+
+	if !p.retryableStatus(ep, resp.StatusCode) {
+		return 0, false
+	}
+	wait := p.backoff(attempt)
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		if p.MaxBackoff > 0 && retryAfter > p.MaxBackoff {
+			return 0, false
+		}
+		wait = retryAfter
+	}
+	return wait, true
+}
+
+func (p *RetryPolicy) retryableStatus(ep endpoint, status int) bool {
+	// a rate limited request has been rejected without being executed
+	if !ep.idempotent {
+		return status == http.StatusTooManyRequests
+	}
+	for _, s := range p.RetryableStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the jittered wait after the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait = wait * (1 - p.Jitter + 2*p.Jitter*rand.Float64())
+	}
+	return time.Duration(wait)
+}
+
+// parseRetryAfter parses both forms of the Retry-After header, delay-seconds and HTTP-date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,514 @@
+package kryptono
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 10 * time.Millisecond
+	return policy
+}
+
+const testOpenOrdersBody = `{
+	"total": 1,
+	"list": [
+	  {
+		"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e",
+		"account_id": "bzbf4991-ad06-44e5-908c-691fdd55da14",
+		"order_symbol": "KNOW_ETH",
+		"order_side": "BUY",
+		"status": "open",
+		"createTime": 1507725176700,
+		"type": "LIMIT",
+		"order_price": "0.0000123",
+		"order_size": "7777",
+		"executed": "0",
+		"stop_price": "0",
+		"avg": "0.0000123",
+		"total": "0.09565710 ETH"
+	  }
+	]
+}`
+
+func testNewOrderRequest() *NewOrderRequest {
+	return &NewOrderRequest{
+		OrderSymbol: "KNOW_ETH",
+		OrderSide:   "BUY",
+		OrderPrice:  MustParseDecimal("0.0000123"),
+		OrderSize:   NewDecimalFromInt(7777),
+		Type:        "LIMIT",
+		Timestamp:   NewTimestampFromMillis(1507725176599),
+		RecvWindow:  5000,
+	}
+}
+
+func TestRetryIdempotentEndpoint(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"result": true}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	resp, err := client.Ping()
+	assert.Nil(t, err)
+	assert.True(t, resp.Result)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	_, err = client.Ping()
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, "key", "secret")
+	assert.Nil(t, err)
+
+	_, err = client.Ping()
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRetryAfterTooLongIsNotRetried(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	_, err = client.Ping()
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	wait, ok := parseRetryAfter("2")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	wait, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), wait)
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		wait := policy.backoff(1)
+		assert.True(t, wait >= 50*time.Millisecond && wait <= 150*time.Millisecond, wait)
+	}
+}
+
+func TestRetryNewOrderNeverSent(t *testing.T) {
+	// nothing listens on a closed listener's address, so the request is never written
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	url := "http://" + listener.Addr().String()
+	listener.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(url), WithTransport(transport), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	_, err = client.NewOrder(testNewOrderRequest())
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrOutcomeUnknown))
+	assert.Equal(t, 4, transport.requests)
+}
+
+func TestRetryNewOrderConfirmedAsPlaced(t *testing.T) {
+	var added int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/add":
+			atomic.AddInt32(&added, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/v2/order/list/open":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(testOpenOrdersBody))
+		case "/api/v2/order/list/completed":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total": 0, "list": []}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	resp, err := client.NewOrder(testNewOrderRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, "02140bef-0c98-4997-9412-9e7ca6f1cc0e", resp.OrderID)
+	assert.Equal(t, "0.0000123", resp.OrderPrice.String())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&added))
+}
+
+func TestRetryNewOrderConfirmedAsNotPlaced(t *testing.T) {
+	var added int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/add":
+			if atomic.AddInt32(&added, 1) == 1 {
+				// drop the connection after the request has been received
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e"}`))
+		case "/api/v2/order/list/open", "/api/v2/order/list/completed":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total": 0, "list": []}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	resp, err := client.NewOrder(testNewOrderRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, "02140bef-0c98-4997-9412-9e7ca6f1cc0e", resp.OrderID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&added))
+}
+
+// testMatchingOrderBody returns a list with an order like testNewOrderRequest, created at createTime.
+func testMatchingOrderBody(orderID string, createTime int64) string {
+	return fmt.Sprintf(`{"total": 1, "list": [{
+		"order_id": %q,
+		"order_symbol": "KNOW_ETH",
+		"order_side": "BUY",
+		"status": "open",
+		"createTime": %d,
+		"type": "LIMIT",
+		"order_price": "0.0000123",
+		"order_size": "7777"
+	}]}`, orderID, createTime)
+}
+
+func TestRetryNewOrderIgnoresEarlierOrder(t *testing.T) {
+	var added int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/add":
+			if atomic.AddInt32(&added, 1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"order_id": "NEW"}`))
+		case "/api/v2/order/list/open":
+			// an identical order placed 10s before the request, outside the window of its recvWindow
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(testMatchingOrderBody("OLD", 1507725176599-10000)))
+		case "/api/v2/order/list/completed":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total": 0, "list": []}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	resp, err := client.NewOrder(testNewOrderRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, "NEW", resp.OrderID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&added))
+}
+
+func TestRetryNewOrderMatchInClockSlack(t *testing.T) {
+	var added int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/add":
+			atomic.AddInt32(&added, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/v2/order/list/open":
+			// the local clock is ahead, so the exchange dates the order before the request
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(testMatchingOrderBody("SKEWED", 1507725176599-300)))
+		case "/api/v2/order/list/completed":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total": 0, "list": []}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	_, err = client.NewOrder(testNewOrderRequest())
+	assert.True(t, errors.Is(err, ErrOutcomeUnknown), err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&added))
+}
+
+func TestRetryNewOrderTruncatedList(t *testing.T) {
+	var added, pages int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/add":
+			atomic.AddInt32(&added, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/v2/order/list/open":
+			atomic.AddInt32(&pages, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total": 120, "list": []}`))
+		case "/api/v2/order/list/completed":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total": 0, "list": []}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	_, err = client.NewOrder(testNewOrderRequest())
+	assert.True(t, errors.Is(err, ErrOutcomeUnknown), err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&added))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pages))
+}
+
+func TestRetryNewOrderPagesThroughList(t *testing.T) {
+	var added int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/add":
+			atomic.AddInt32(&added, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/v2/order/list/open":
+			var request OpenOrdersRequest
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Nil(t, json.Unmarshal(body, &request))
+			w.WriteHeader(http.StatusOK)
+			if request.Page == 0 {
+				// a page of other orders, not sorted by time
+				orders := make([]string, confirmOrdersLimit)
+				for i := range orders {
+					orders[i] = fmt.Sprintf(`{"order_id": "OTHER%d", "order_symbol": "KNOW_ETH", "order_side": "SELL", "createTime": %d, "order_size": "1"}`, i, 1507725176700+int64(i%2))
+				}
+				w.Write([]byte(fmt.Sprintf(`{"total": %d, "list": [%s]}`, confirmOrdersLimit+1, strings.Join(orders, ","))))
+				return
+			}
+			w.Write([]byte(strings.Replace(testMatchingOrderBody("PLACED", 1507725176700), `"total": 1`, fmt.Sprintf(`"total": %d`, confirmOrdersLimit+1), 1)))
+		case "/api/v2/order/list/completed":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total": 0, "list": []}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	resp, err := client.NewOrder(testNewOrderRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, "PLACED", resp.OrderID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&added))
+}
+
+func TestRetryNewOrderAmbiguousMatch(t *testing.T) {
+	var added int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/add":
+			atomic.AddInt32(&added, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/v2/order/list/open":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(testMatchingOrderBody("FIRST", 1507725176700)))
+		case "/api/v2/order/list/completed":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(testMatchingOrderBody("SECOND", 1507725176800)))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	resp, err := client.NewOrder(testNewOrderRequest())
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrOutcomeUnknown), err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&added))
+}
+
+func TestRetryNewOrderOutcomeUnknown(t *testing.T) {
+	var added int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/add":
+			atomic.AddInt32(&added, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	_, err = client.NewOrder(testNewOrderRequest())
+	assert.True(t, errors.Is(err, ErrOutcomeUnknown), err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&added))
+}
+
+func TestNewOrderDeadlineAfterSent(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	for _, options := range [][]ClientOption{nil, {WithRetryPolicy(testRetryPolicy())}} {
+		client, err := NewClient("key", "secret", append(options, WithAccountAPIEndpoint(ts.URL))...)
+		assert.Nil(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err = client.NewOrderContext(ctx, testNewOrderRequest())
+		cancel()
+		assert.True(t, errors.Is(err, ErrOutcomeUnknown), err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	}
+}
+
+func TestRetryCancelOrderConfirmedAsCanceled(t *testing.T) {
+	var canceled int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/cancel":
+			atomic.AddInt32(&canceled, 1)
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case "/api/v2/order/details":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e", "order_symbol": "KNOW_ETH", "status": "canceled"}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	resp, err := client.CancelOrder(&CancelOrderRequest{
+		OrderID:     "02140bef-0c98-4997-9412-9e7ca6f1cc0e",
+		OrderSymbol: "KNOW_ETH",
+		Timestamp:   NewTimestampFromMillis(1429514463299),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "02140bef-0c98-4997-9412-9e7ca6f1cc0e", resp.OrderID)
+	assert.Equal(t, Market("KNOW_ETH"), resp.OrderSymbol)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&canceled))
+}
+
+func TestRetryCancelOrderStillOpen(t *testing.T) {
+	var canceled int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/order/cancel":
+			if atomic.AddInt32(&canceled, 1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e", "order_symbol": "KNOW_ETH"}`))
+		case "/api/v2/order/details":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e", "order_symbol": "KNOW_ETH", "status": "open"}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithRetryPolicy(testRetryPolicy()))
+	assert.Nil(t, err)
+
+	resp, err := client.CancelOrder(&CancelOrderRequest{
+		OrderID:     "02140bef-0c98-4997-9412-9e7ca6f1cc0e",
+		OrderSymbol: "KNOW_ETH",
+		Timestamp:   NewTimestampFromMillis(1429514463299),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "02140bef-0c98-4997-9412-9e7ca6f1cc0e", resp.OrderID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&canceled))
+}
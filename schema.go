@@ -0,0 +1,228 @@
+package kryptono
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SchemaDriftKind is the kind of difference between a response and its struct.
+type SchemaDriftKind string
+
+const (
+	// SchemaDriftUnknownField is a field of the response the struct doesn't have.
+	SchemaDriftUnknownField SchemaDriftKind = "unknown_field"
+	// SchemaDriftTypeMismatch is a value of the response that can't be decoded into its field.
+	SchemaDriftTypeMismatch SchemaDriftKind = "type_mismatch"
+)
+
+// SchemaDrift is a difference between a response and the struct it is decoded into.
+type SchemaDrift struct {
+	Endpoint string
+	// Path of the value in the response, e.g. "list[0].order_price"
+	Path    string
+	Kind    SchemaDriftKind
+	Message string
+}
+
+func (d SchemaDrift) String() string {
+	return fmt.Sprintf("%s: %s at %s: %s", d.Endpoint, d.Kind, d.Path, d.Message)
+}
+
+// WithSchemaDriftHandler makes the client check every response for fields its struct doesn't have and
+// values that can't be decoded, and report each of them to handler. Values that can't be decoded are
+// left unset, and the rest of the response is still returned. Without it unknown fields are ignored
+// and a value that can't be decoded fails the call.
+func WithSchemaDriftHandler(handler func(drift SchemaDrift)) ClientOption {
+	return func(c *client) error {
+		if handler == nil {
+			return errors.New("schema drift handler must not be nil")
+		}
+		c.schemaDrift = handler
+		return nil
+	}
+}
+
+// decodeDrifting decodes data into result, reporting schema drift to the client's handler.
+func (c *client) decodeDrifting(endpoint string, data []byte, result interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	w := &schemaWalker{endpoint: endpoint}
+	sanitized, ok := w.walk("", raw, reflect.TypeOf(result).Elem())
+	for _, drift := range w.drifts {
+		c.schemaDrift(drift)
+	}
+	if !ok {
+		return nil
+	}
+	// decode what matches the struct
+	data, err := json.Marshal(sanitized)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+type schemaWalker struct {
+	endpoint string
+	drifts   []SchemaDrift
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// walk compares value with type t. It returns value with everything not matching t removed,
+// ok is false if value doesn't match at all.
+func (w *schemaWalker) walk(path string, value interface{}, t reflect.Type) (interface{}, bool) {
+	if value == nil {
+		return nil, true
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return value, w.decodes(path, value, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, isObject := value.(map[string]interface{})
+		if !isObject {
+			return nil, w.mismatch(path, value, t)
+		}
+		fields := jsonFields(t)
+		sanitized := make(map[string]interface{}, len(object))
+		for key, v := range object {
+			field, known := lookupField(fields, key)
+			if !known {
+				w.report(joinPath(path, key), SchemaDriftUnknownField, "field is not decoded")
+				continue
+			}
+			if v, ok := w.walk(joinPath(path, key), v, field.Type); ok {
+				sanitized[key] = v
+			}
+		}
+		return sanitized, true
+	case reflect.Slice, reflect.Array:
+		array, isArray := value.([]interface{})
+		if !isArray {
+			return nil, w.mismatch(path, value, t)
+		}
+		sanitized := make([]interface{}, len(array))
+		for i, v := range array {
+			// keep the position, a null element decodes to the zero value
+			if v, ok := w.walk(fmt.Sprintf("%s[%d]", path, i), v, t.Elem()); ok {
+				sanitized[i] = v
+			}
+		}
+		return sanitized, true
+	case reflect.Map:
+		object, isObject := value.(map[string]interface{})
+		if !isObject {
+			return nil, w.mismatch(path, value, t)
+		}
+		sanitized := make(map[string]interface{}, len(object))
+		for key, v := range object {
+			if v, ok := w.walk(joinPath(path, key), v, t.Elem()); ok {
+				sanitized[key] = v
+			}
+		}
+		return sanitized, true
+	case reflect.Interface:
+		return value, true
+	default:
+		return value, w.decodes(path, value, t)
+	}
+}
+
+// decodes tells whether value can be decoded into t, and reports it if it can't.
+func (w *schemaWalker) decodes(path string, value interface{}, t reflect.Type) bool {
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, reflect.New(t).Interface())
+	}
+	if err != nil {
+		w.report(path, SchemaDriftTypeMismatch, fmt.Sprintf("can't decode %s into %s: %v", data, t, err))
+		return false
+	}
+	return true
+}
+
+func (w *schemaWalker) mismatch(path string, value interface{}, t reflect.Type) bool {
+	w.report(path, SchemaDriftTypeMismatch, fmt.Sprintf("can't decode %s into %s", jsonKind(value), t))
+	return false
+}
+
+func (w *schemaWalker) report(path string, kind SchemaDriftKind, message string) {
+	if path == "" {
+		path = "."
+	}
+	w.drifts = append(w.drifts, SchemaDrift{Endpoint: w.endpoint, Path: path, Kind: kind, Message: message})
+}
+
+// jsonFields returns the fields of struct type t by their JSON name.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for n, f := range jsonFields(field.Type) {
+				fields[n] = f
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// lookupField finds the field for key like encoding/json, preferring an exact match over a case-insensitive one.
+func lookupField(fields map[string]reflect.StructField, key string) (reflect.StructField, bool) {
+	if field, ok := fields[key]; ok {
+		return field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
@@ -0,0 +1,109 @@
+package kryptono
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaDriftReported(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"order_id": "0e3f05e0-912c-4957-9322-d1a34ef6e312",
+			"order_symbol": "KNOW_BTC",
+			"order_price": "n/a",
+			"order_size": "1000",
+			"createTime": {"ms": 1429514463266},
+			"status": 1,
+			"fee_currency": "KNOW"
+		}`))
+	}))
+	defer ts.Close()
+
+	var mu sync.Mutex
+	var drifts []SchemaDrift
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithSchemaDriftHandler(func(drift SchemaDrift) {
+		mu.Lock()
+		defer mu.Unlock()
+		drifts = append(drifts, drift)
+	}))
+	assert.Nil(t, err)
+
+	resp, err := client.OrderDetail(&OrderDetailRequest{OrderID: "0e3f05e0-912c-4957-9322-d1a34ef6e312"})
+	assert.Nil(t, err)
+	assert.Equal(t, "0e3f05e0-912c-4957-9322-d1a34ef6e312", resp.OrderID)
+	assert.Equal(t, "1000", resp.OrderSize.String())
+	assert.True(t, resp.OrderPrice.IsZero())
+	assert.True(t, resp.CreateTime.IsZero())
+
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Path < drifts[j].Path })
+	assert.Len(t, drifts, 4)
+	assert.Equal(t, "OrderDetail", drifts[0].Endpoint)
+	assert.Equal(t, "createTime", drifts[0].Path)
+	assert.Equal(t, SchemaDriftTypeMismatch, drifts[0].Kind)
+	assert.Contains(t, drifts[0].Message, "can't decode {\"ms\":1429514463266} into kryptono.Timestamp")
+	assert.Equal(t, "fee_currency", drifts[1].Path)
+	assert.Equal(t, SchemaDriftUnknownField, drifts[1].Kind)
+	assert.Equal(t, "order_price", drifts[2].Path)
+	assert.Equal(t, SchemaDriftTypeMismatch, drifts[2].Kind)
+	assert.Equal(t, "status", drifts[3].Path)
+}
+
+func TestSchemaDriftInLists(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"symbol": "KNOW_BTC", "asks": [["0.1", "5"], ["0.2"]], "bids": {"0.05": "1"}, "time": 1574517091326}`))
+	}))
+	defer ts.Close()
+
+	var drifts []SchemaDrift
+	client, err := NewClient("key", "secret", WithMarketAPIEndpoint(ts.URL), WithSchemaDriftHandler(func(drift SchemaDrift) {
+		drifts = append(drifts, drift)
+	}))
+	assert.Nil(t, err)
+
+	resp, err := client.OrderBook("KNOW_BTC")
+	assert.Nil(t, err)
+	assert.Len(t, resp.Asks, 2)
+	assert.Equal(t, "5", resp.Asks[0].Size.String())
+	assert.Nil(t, resp.Bids)
+	assert.Equal(t, int64(1574517091326), resp.Time.Millis())
+
+	assert.Len(t, drifts, 2)
+	for _, drift := range drifts {
+		assert.Contains(t, []string{"asks[1]", "bids"}, drift.Path)
+		assert.Equal(t, SchemaDriftTypeMismatch, drift.Kind)
+	}
+}
+
+func TestSchemaDriftWithoutHandlerFails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"server_time": "yesterday", "unknown": true}`))
+	}))
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, "key", "secret")
+	assert.Nil(t, err)
+	_, err = client.ServerTime()
+	assert.NotNil(t, err)
+
+	_, err = NewClient("key", "secret", WithSchemaDriftHandler(nil))
+	assert.NotNil(t, err)
+}
+
+func TestSchemaDriftResponseTooLarge(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result": true, "padding": "0123456789"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithGeneralAPIEndpoint(ts.URL), WithMaxResponseSize(16),
+		WithSchemaDriftHandler(func(drift SchemaDrift) {}))
+	assert.Nil(t, err)
+	_, err = client.Ping()
+	assert.True(t, errors.Is(err, ErrResponseTooLarge), err)
+}
@@ -0,0 +1,156 @@
+package kryptono
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+)
+
+// Signer computes the Signature header of a signed request from its body.
+type Signer interface {
+	Sign(ctx context.Context, payload []byte) (string, error)
+}
+
+// WithSigner makes the client sign requests with signer instead of the API secret. With a signer
+// that delegates to another process, NewClient can be called with an empty apiSecret.
+func WithSigner(signer Signer) ClientOption {
+	return func(c *client) error {
+		if signer == nil {
+			return errors.New("signer must not be nil")
+		}
+		c.signer = signer
+		return nil
+	}
+}
+
+// HMACSigner signs payloads with HMAC-SHA256 of the API secret, as expected by the exchange.
+// It is the client's default signer.
+type HMACSigner struct {
+	secret []byte
+}
+
+// NewHMACSigner creates a signer for apiSecret.
+func NewHMACSigner(apiSecret string) *HMACSigner {
+	return &HMACSigner{secret: []byte(apiSecret)}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of payload.
+func (s *HMACSigner) Sign(ctx context.Context, payload []byte) (string, error) {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// socketSignRequest and socketSignResponse make up the protocol of UnixSocketSigner, one JSON
+// object per line in each direction.
+type socketSignRequest struct {
+	Payload []byte `json:"payload"`
+}
+
+type socketSignResponse struct {
+	Signature string `json:"signature"`
+	Error     string `json:"error,omitempty"`
+}
+
+// UnixSocketSigner delegates signing to a process listening on a Unix socket. For every payload it
+// opens a connection, writes {"payload": "<base64 of payload>"} followed by a newline, and expects
+// {"signature": "<hex signature>"} or {"error": "<message>"} followed by a newline in return.
+type UnixSocketSigner struct {
+	path   string
+	dialer net.Dialer
+}
+
+// NewUnixSocketSigner creates a signer talking to the socket at path.
+func NewUnixSocketSigner(path string) *UnixSocketSigner {
+	return &UnixSocketSigner{path: path}
+}
+
+// Sign asks the signing process for the signature of payload. Cancelling ctx closes the connection.
+func (s *UnixSocketSigner) Sign(ctx context.Context, payload []byte) (string, error) {
+	conn, err := s.dialer.DialContext(ctx, "unix", s.path)
+	if err != nil {
+		return "", fmt.Errorf("error connecting to signer, %v", err)
+	}
+	defer conn.Close()
+	// closing the connection unblocks a signer that doesn't answer
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	if err := json.NewEncoder(conn).Encode(socketSignRequest{Payload: payload}); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("error sending payload to signer, %v", err)
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("error reading signature from signer, %v", err)
+	}
+	var resp socketSignResponse
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return "", fmt.Errorf("invalid response from signer, %v", err)
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("signer failed, %s", resp.Error)
+	}
+	if resp.Signature == "" {
+		return "", errors.New("signer returned no signature")
+	}
+	return resp.Signature, nil
+}
+
+// CommandSigner delegates signing to a command. The command is run for every payload, gets the
+// payload on stdin and has to print the hex signature to stdout and exit with status 0.
+type CommandSigner struct {
+	name string
+	args []string
+}
+
+// NewCommandSigner creates a signer running the command name with args.
+func NewCommandSigner(name string, args ...string) *CommandSigner {
+	return &CommandSigner{name: name, args: args}
+}
+
+// Sign runs the command to get the signature of payload.
+func (s *CommandSigner) Sign(ctx context.Context, payload []byte) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.name, s.args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("signer command failed, %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	signature := strings.TrimSpace(stdout.String())
+	if signature == "" {
+		return "", errors.New("signer command printed no signature")
+	}
+	return signature, nil
+}
+
+// sign returns the signature of body, made by the configured signer or with the API secret.
+func (c *client) sign(ctx context.Context, body []byte) (string, error) {
+	signer := c.signer
+	if signer == nil {
+		signer = NewHMACSigner(c.auth.APISecret)
+	}
+	signature, err := signer.Sign(ctx, body)
+	if err != nil {
+		return "", fmt.Errorf("error signing request, %w", err)
+	}
+	return signature, nil
+}
@@ -0,0 +1,150 @@
+package kryptono
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSignerSecret  = "4a894c5c-8a7e-4337-bb6b-9fde16e3dddd"
+	testSignerPayload = `{"order_id":"02140bef-0c98-4997-9412-9e7ca6f1cc0e"}`
+)
+
+func TestHMACSigner(t *testing.T) {
+	signature, err := NewHMACSigner(testSignerSecret).Sign(context.Background(), []byte(`{"timestamp":1530682938651,"recvWindow":5000}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "f6f8463e8c41574ccb2bfc46938868b78b0b6a1a0cb75cf58a92eba3d6535b9f", signature)
+}
+
+func TestUnixSocketSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signer.sock")
+
+	listener, err := net.Listen("unix", path)
+	assert.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadBytes('\n')
+			var req socketSignRequest
+			json.Unmarshal(line, &req)
+			signature, _ := NewHMACSigner(testSignerSecret).Sign(context.Background(), req.Payload)
+			json.NewEncoder(conn).Encode(socketSignResponse{Signature: signature})
+			conn.Close()
+		}
+	}()
+
+	expected, _ := NewHMACSigner(testSignerSecret).Sign(context.Background(), []byte(testSignerPayload))
+	signature, err := NewUnixSocketSigner(path).Sign(context.Background(), []byte(testSignerPayload))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, signature)
+
+	_, err = NewUnixSocketSigner(filepath.Join(dir, "missing.sock")).Sign(context.Background(), []byte(testSignerPayload))
+	assert.NotNil(t, err)
+}
+
+func TestUnixSocketSignerCanceled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signer.sock")
+
+	// a signer that accepts connections but never answers
+	listener, err := net.Listen("unix", path)
+	assert.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	done := make(chan error)
+	go func() {
+		_, err := NewUnixSocketSigner(path).Sign(ctx, []byte(testSignerPayload))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("signing wasn't canceled")
+	}
+}
+
+// TestHelperSignerProcess isn't a real test, it is run as signer command by TestCommandSigner.
+func TestHelperSignerProcess(t *testing.T) {
+	if os.Getenv("KRYPTONO_HELPER_SIGNER") != "1" {
+		return
+	}
+	payload, _ := ioutil.ReadAll(os.Stdin)
+	signature, _ := NewHMACSigner(testSignerSecret).Sign(context.Background(), payload)
+	fmt.Println(signature)
+	os.Exit(0)
+}
+
+func TestCommandSigner(t *testing.T) {
+	os.Setenv("KRYPTONO_HELPER_SIGNER", "1")
+	defer os.Unsetenv("KRYPTONO_HELPER_SIGNER")
+
+	expected, _ := NewHMACSigner(testSignerSecret).Sign(context.Background(), []byte(testSignerPayload))
+	signature, err := NewCommandSigner(os.Args[0], "-test.run=TestHelperSignerProcess").Sign(context.Background(), []byte(testSignerPayload))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, signature)
+
+	_, err = NewCommandSigner(filepath.Join(os.TempDir(), "no-such-signer")).Sign(context.Background(), []byte(testSignerPayload))
+	assert.NotNil(t, err)
+}
+
+type staticSigner string
+
+func (s staticSigner) Sign(ctx context.Context, payload []byte) (string, error) {
+	return string(s), nil
+}
+
+func TestClientWithSigner(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.Header.Get(HeaderAuthorization))
+		assert.Equal(t, "signed elsewhere", r.Header.Get(HeaderSignature))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "", WithAccountAPIEndpoint(ts.URL), WithSigner(staticSigner("signed elsewhere")))
+	assert.Nil(t, err)
+
+	_, err = client.AccountBalances(&AccountBalancesRequest{})
+	assert.Nil(t, err)
+
+	_, err = NewClient("key", "", WithSigner(nil))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,68 @@
+package kryptono
+
+import "strings"
+
+// Market is a market of a base and a quote currency. The v2 endpoints name markets "KNOW_BTC",
+// MarketSummaries names them "KNOW-BTC", Market understands both. It is encoded in the spelling
+// it was created with.
+type Market string
+
+// NewMarket returns the market of base and quote in the "BASE_QUOTE" format of the v2 endpoints.
+func NewMarket(base string, quote string) Market {
+	return Market(strings.ToUpper(base) + "_" + strings.ToUpper(quote))
+}
+
+// split returns the upper case base and quote currency, ok is false if m isn't a market name.
+func (m Market) split() (base string, quote string, ok bool) {
+	i := strings.IndexAny(string(m), "_-/")
+	if i <= 0 || i == len(m)-1 || strings.ContainsAny(string(m[i+1:]), "_-/") {
+		return "", "", false
+	}
+	return strings.ToUpper(strings.TrimSpace(string(m[:i]))), strings.ToUpper(strings.TrimSpace(string(m[i+1:]))), true
+}
+
+// IsValid tells whether m consists of a base and a quote currency.
+func (m Market) IsValid() bool {
+	_, _, ok := m.split()
+	return ok
+}
+
+// Base returns the base currency, e.g. "KNOW" for "KNOW_BTC", or "" if m isn't valid.
+func (m Market) Base() string {
+	base, _, _ := m.split()
+	return base
+}
+
+// Quote returns the quote currency, e.g. "BTC" for "KNOW_BTC", or "" if m isn't valid.
+func (m Market) Quote() string {
+	_, quote, _ := m.split()
+	return quote
+}
+
+// Symbol returns m in the "BASE_QUOTE" format of the v2 endpoints. Invalid markets are returned as is.
+func (m Market) Symbol() string {
+	return m.format("_")
+}
+
+// SummaryName returns m in the "BASE-QUOTE" format of MarketSummaries. Invalid markets are returned as is.
+func (m Market) SummaryName() string {
+	return m.format("-")
+}
+
+// Equal tells whether m and other are the same market, regardless of their spelling.
+func (m Market) Equal(other Market) bool {
+	return m.Symbol() == other.Symbol()
+}
+
+// String returns m in the spelling it was created with.
+func (m Market) String() string {
+	return string(m)
+}
+
+func (m Market) format(separator string) string {
+	base, quote, ok := m.split()
+	if !ok {
+		return string(m)
+	}
+	return base + separator + quote
+}
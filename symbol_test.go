@@ -0,0 +1,63 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarket(t *testing.T) {
+	for _, m := range []Market{"KNOW_BTC", "KNOW-BTC", "know_btc", "KNOW/BTC"} {
+		assert.True(t, m.IsValid(), m)
+		assert.Equal(t, "KNOW", m.Base(), m)
+		assert.Equal(t, "BTC", m.Quote(), m)
+		assert.Equal(t, "KNOW_BTC", m.Symbol(), m)
+		assert.Equal(t, "KNOW-BTC", m.SummaryName(), m)
+		assert.True(t, m.Equal(NewMarket("know", "btc")), m)
+	}
+	assert.Equal(t, "know-btc", Market("know-btc").String())
+
+	for _, m := range []Market{"", "KNOW", "_BTC", "KNOW_", "A_B_C"} {
+		assert.False(t, m.IsValid(), m)
+		assert.Equal(t, "", m.Base(), m)
+		assert.Equal(t, string(m), m.Symbol(), m)
+	}
+	assert.False(t, Market("KNOW_BTC").Equal("KNOW_ETH"))
+}
+
+func TestMarketJSON(t *testing.T) {
+	var summary MarketSummariesRespElement
+	assert.Nil(t, json.Unmarshal([]byte(`{"MarketName": "EOS-BTC"}`), &summary))
+	assert.Equal(t, "EOS_BTC", summary.MarketName.Symbol())
+	assert.True(t, summary.MarketName.Equal("EOS_BTC"))
+
+	output, err := json.Marshal(summary.MarketName)
+	assert.Nil(t, err)
+	assert.Equal(t, `"EOS-BTC"`, string(output))
+}
+
+func TestMarketIsSentInSymbolFormat(t *testing.T) {
+	var query, body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client, err := newClientWithURL(ts.URL, "key", "secret")
+	assert.Nil(t, err)
+
+	_, err = client.OrderBook("know-btc")
+	assert.Nil(t, err)
+	assert.Equal(t, "symbol=KNOW_BTC", query)
+
+	_, err = client.OpenOrders(&OpenOrdersRequest{Symbol: "know-btc", Timestamp: NewTimestampFromMillis(1429514463299), RecvWindow: 5000})
+	assert.Nil(t, err)
+	assert.Contains(t, body, `"symbol":"KNOW_BTC"`)
+}
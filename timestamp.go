@@ -0,0 +1,123 @@
+package kryptono
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timestampFormat is the JSON encoding a Timestamp was decoded from.
+type timestampFormat uint8
+
+const (
+	// milliseconds since the epoch as number
+	timestampMillis timestampFormat = iota
+	// milliseconds since the epoch as string
+	timestampMillisString
+	// RFC 3339 string
+	timestampText
+)
+
+// Timestamp is a point in time as sent by the exchange, usually milliseconds since the epoch.
+// It decodes milliseconds as JSON number or string as well as RFC 3339 strings, and encodes
+// back to the format it was decoded from. New timestamps are encoded as milliseconds. The zero
+// value is unset and encodes as 0.
+type Timestamp struct {
+	t      time.Time
+	format timestampFormat
+	// the original text of timestampText, so it round-trips unchanged
+	raw string
+}
+
+// NewTimestamp returns t as Timestamp.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{t: t}
+}
+
+// NewTimestampFromMillis returns the Timestamp of ms milliseconds since the epoch, 0 is unset.
+func NewTimestampFromMillis(ms int64) Timestamp {
+	if ms == 0 {
+		return Timestamp{}
+	}
+	return Timestamp{t: time.UnixMilli(ms)}
+}
+
+// Time returns ts as time.Time, the zero time if ts is unset.
+func (ts Timestamp) Time() time.Time {
+	return ts.t
+}
+
+// Millis returns the milliseconds since the epoch, 0 if ts is unset.
+func (ts Timestamp) Millis() int64 {
+	if ts.t.IsZero() {
+		return 0
+	}
+	return ts.t.UnixMilli()
+}
+
+// IsZero tells whether ts is unset.
+func (ts Timestamp) IsZero() bool {
+	return ts.t.IsZero()
+}
+
+// String returns ts in RFC 3339 format with milliseconds.
+func (ts Timestamp) String() string {
+	return ts.t.Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// MarshalJSON encodes ts in the format it was decoded from.
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	switch ts.format {
+	case timestampMillisString:
+		return []byte(strconv.Quote(strconv.FormatInt(ts.Millis(), 10))), nil
+	case timestampText:
+		if ts.raw != "" {
+			return json.Marshal(ts.raw)
+		}
+		return json.Marshal(ts.t.Format(time.RFC3339Nano))
+	default:
+		return []byte(strconv.FormatInt(ts.Millis(), 10)), nil
+	}
+}
+
+// UnmarshalJSON decodes milliseconds as JSON number or string, or an RFC 3339 string.
+// null and "" decode to an unset Timestamp.
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*ts = Timestamp{}
+		return nil
+	}
+
+	if len(data) == 0 || data[0] != '"' {
+		ms, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp %s", data)
+		}
+		*ts = NewTimestampFromMillis(ms)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*ts = Timestamp{}
+		return nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*ts = NewTimestampFromMillis(ms)
+		ts.format = timestampMillisString
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			*ts = Timestamp{t: t, format: timestampText, raw: s}
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid timestamp %q", s)
+}
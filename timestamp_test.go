@@ -0,0 +1,50 @@
+package kryptono
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampJSON(t *testing.T) {
+	var value struct {
+		Number Timestamp `json:"number"`
+		Str    Timestamp `json:"str"`
+		Text   Timestamp `json:"text"`
+		Local  Timestamp `json:"local"`
+		Null   Timestamp `json:"null"`
+	}
+	input := `{"number":1574515989114,"str":"1574515989127","text":"2019-11-24T11:06:16.080Z","local":"2019-11-24T11:06:16","null":null}`
+	assert.Nil(t, json.Unmarshal([]byte(input), &value))
+
+	assert.Equal(t, int64(1574515989114), value.Number.Millis())
+	assert.Equal(t, int64(1574515989127), value.Str.Millis())
+	assert.True(t, value.Text.Time().Equal(time.Date(2019, 11, 24, 11, 6, 16, 80*int(time.Millisecond), time.UTC)))
+	assert.Equal(t, int64(1574593576000), value.Local.Millis())
+	assert.True(t, value.Null.IsZero())
+
+	output, err := json.Marshal(value)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"number":1574515989114,"str":"1574515989127","text":"2019-11-24T11:06:16.080Z","local":"2019-11-24T11:06:16","null":0}`, string(output))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"yesterday"`), &value.Text))
+	assert.NotNil(t, json.Unmarshal([]byte(`1.5`), &value.Number))
+}
+
+func TestTimestampConversion(t *testing.T) {
+	now := time.Date(2019, 11, 24, 11, 6, 16, 81*int(time.Millisecond), time.UTC)
+	ts := NewTimestamp(now)
+	assert.True(t, ts.Time().Equal(now))
+	assert.Equal(t, int64(1574593576081), ts.Millis())
+	assert.True(t, NewTimestampFromMillis(1574593576081).Time().Equal(now))
+	assert.Equal(t, "2019-11-24T11:06:16.081Z", NewTimestamp(now).String())
+
+	assert.True(t, NewTimestampFromMillis(0).IsZero())
+	assert.Equal(t, int64(0), Timestamp{}.Millis())
+
+	output, err := json.Marshal(ts)
+	assert.Nil(t, err)
+	assert.Equal(t, "1574593576081", string(output))
+}
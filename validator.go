@@ -0,0 +1,182 @@
+package kryptono
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ViolationRule is an exchange rule an order violates.
+type ViolationRule string
+
+const (
+	// ViolationUnknownMarket is an order in a market the exchange doesn't list.
+	ViolationUnknownMarket ViolationRule = "unknown_market"
+	// ViolationTradingDisabled is an order in a market not allowing trading.
+	ViolationTradingDisabled ViolationRule = "trading_disabled"
+	// ViolationPricePrecision is a price or stop price with more decimal places than the market allows.
+	ViolationPricePrecision ViolationRule = "price_precision"
+	// ViolationSizePrecision is a size with more decimal places than the market allows.
+	ViolationSizePrecision ViolationRule = "size_precision"
+	// ViolationMinimumSize is a size below the minimum order amount of the base currency.
+	ViolationMinimumSize ViolationRule = "minimum_size"
+	// ViolationMinimumNotional is an order worth less than the minimum total order of the quote currency.
+	ViolationMinimumNotional ViolationRule = "minimum_notional"
+)
+
+// Violation is a rule of the exchange an order violates.
+type Violation struct {
+	Rule ViolationRule
+	// Field is the JSON name of the offending field of NewOrderRequest
+	Field   string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// OrderViolationsError is returned for orders violating exchange rules, it wraps ErrInvalidParams.
+type OrderViolationsError struct {
+	Violations []Violation
+}
+
+func (e *OrderViolationsError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.String()
+	}
+	return fmt.Sprintf("%v: order violates exchange rules: %s", ErrInvalidParams, strings.Join(messages, "; "))
+}
+
+func (e *OrderViolationsError) Unwrap() error {
+	return ErrInvalidParams
+}
+
+// exchangeRules are the trading rules of the exchange from ExchangeInformation.
+type exchangeRules struct {
+	symbols map[string]Symbol
+	// minimum order amount by currency
+	minimumSizes map[string]Decimal
+	// minimum total order by quote currency
+	minimumNotionals map[string]Decimal
+}
+
+func newExchangeRules(info *ExchangeInformationResp) *exchangeRules {
+	r := &exchangeRules{
+		symbols:          make(map[string]Symbol, len(info.Symbols)),
+		minimumSizes:     make(map[string]Decimal, len(info.Coins)),
+		minimumNotionals: make(map[string]Decimal, len(info.BaseCurrencies)),
+	}
+	for _, s := range info.Symbols {
+		r.symbols[s.Symbol.Symbol()] = s
+	}
+	for _, c := range info.Coins {
+		r.minimumSizes[strings.ToUpper(c.CurrencyCode)] = c.MinimumOrderAmount
+	}
+	for _, b := range info.BaseCurrencies {
+		r.minimumNotionals[strings.ToUpper(b.CurrencyCode)] = b.MinimumTotalOrder
+	}
+	return r
+}
+
+func (r *exchangeRules) symbol(market Market) (Symbol, bool) {
+	symbol, ok := r.symbols[market.Symbol()]
+	return symbol, ok
+}
+
+// OrderValidator checks orders against the rules of the exchange from ExchangeInformation.
+type OrderValidator struct {
+	rules *exchangeRules
+}
+
+// NewOrderValidator returns a validator for the rules in info.
+func NewOrderValidator(info *ExchangeInformationResp) *OrderValidator {
+	return &OrderValidator{rules: newExchangeRules(info)}
+}
+
+// Validate returns the rules request violates, nil if it violates none. Market orders have no
+// price, their minimum notional isn't checked.
+func (v *OrderValidator) Validate(request *NewOrderRequest) []Violation {
+	var violations []Violation
+	add := func(rule ViolationRule, field string, format string, args ...interface{}) {
+		violations = append(violations, Violation{Rule: rule, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	rules := v.rules
+	market := request.OrderSymbol
+	symbol, ok := rules.symbol(market)
+	if !ok {
+		add(ViolationUnknownMarket, "order_symbol", "market %s is not listed", market)
+		return violations
+	}
+	if !symbol.AllowTrading {
+		add(ViolationTradingDisabled, "order_symbol", "trading is disabled for %s", market.Symbol())
+	}
+
+	pricePlaces := int32(symbol.PriceLimitDecimal)
+	if places := request.OrderPrice.places(); places > pricePlaces {
+		add(ViolationPricePrecision, "order_price", "price %s has %d decimal places, %s allows %d", request.OrderPrice, places, market.Symbol(), pricePlaces)
+	}
+	if request.StopPrice != nil {
+		if places := request.StopPrice.places(); places > pricePlaces {
+			add(ViolationPricePrecision, "stop_price", "stop price %s has %d decimal places, %s allows %d", request.StopPrice, places, market.Symbol(), pricePlaces)
+		}
+	}
+	sizePlaces := int32(symbol.AmountLimitDecimal)
+	if places := request.OrderSize.places(); places > sizePlaces {
+		add(ViolationSizePrecision, "order_size", "size %s has %d decimal places, %s allows %d", request.OrderSize, places, market.Symbol(), sizePlaces)
+	}
+
+	if minimum, ok := rules.minimumSizes[market.Base()]; ok && request.OrderSize.LessThan(minimum) {
+		add(ViolationMinimumSize, "order_size", "size %s is below the minimum of %s %s", request.OrderSize, minimum, market.Base())
+	}
+	if minimum, ok := rules.minimumNotionals[market.Quote()]; ok && !request.OrderPrice.IsZero() {
+		if notional := request.OrderPrice.Mul(request.OrderSize); notional.LessThan(minimum) {
+			add(ViolationMinimumNotional, "order_size", "total %s %s is below the minimum of %s %s", notional, market.Quote(), minimum, market.Quote())
+		}
+	}
+	return violations
+}
+
+// Check returns an *OrderViolationsError if request violates any rule.
+func (v *OrderValidator) Check(request *NewOrderRequest) error {
+	if violations := v.Validate(request); len(violations) > 0 {
+		return &OrderViolationsError{Violations: violations}
+	}
+	return nil
+}
+
+// WithOrderValidator makes NewOrder check every order with validator before sending it, orders
+// violating a rule fail with an *OrderViolationsError. The rules of validator are fixed, to check
+// against the current rules use WithOrderValidatorSource.
+func WithOrderValidator(validator *OrderValidator) ClientOption {
+	return func(c *client) error {
+		if validator == nil {
+			return errors.New("order validator must not be nil")
+		}
+		c.validators = func(ctx context.Context) (*OrderValidator, error) {
+			return validator, nil
+		}
+		return nil
+	}
+}
+
+// OrderValidatorSource provides the validator for the current rules, MetadataCache is one.
+type OrderValidatorSource interface {
+	OrderValidator(ctx context.Context) (*OrderValidator, error)
+}
+
+// WithOrderValidatorSource makes NewOrder check every order before sending it with the validator
+// source returns at that time, orders violating a rule fail with an *OrderViolationsError. If
+// source fails, so does NewOrder.
+func WithOrderValidatorSource(source OrderValidatorSource) ClientOption {
+	return func(c *client) error {
+		if source == nil {
+			return errors.New("order validator source must not be nil")
+		}
+		c.validators = source.OrderValidator
+		return nil
+	}
+}
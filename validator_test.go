@@ -0,0 +1,136 @@
+package kryptono
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testExchangeInformation() *ExchangeInformationResp {
+	return &ExchangeInformationResp{
+		BaseCurrencies: []BaseCurrency{{CurrencyCode: "BTC", MinimumTotalOrder: MustParseDecimal("0.001")}},
+		Coins:          []Coin{{CurrencyCode: "KNOW", MinimumOrderAmount: NewDecimalFromInt(10)}},
+		Symbols: []Symbol{
+			{Symbol: "KNOW_BTC", AmountLimitDecimal: 2, PriceLimitDecimal: 8, AllowTrading: true},
+			{Symbol: "GTO_BTC", AmountLimitDecimal: 0, PriceLimitDecimal: 8, AllowTrading: false},
+		},
+	}
+}
+
+func rules(violations []Violation) []ViolationRule {
+	result := make([]ViolationRule, len(violations))
+	for i, v := range violations {
+		result[i] = v.Rule
+	}
+	return result
+}
+
+func TestOrderValidator(t *testing.T) {
+	v := NewOrderValidator(testExchangeInformation())
+
+	valid, _ := LimitOrder("know-btc").Buy().Price(MustParseDecimal("0.00001230")).Size(MustParseDecimal("100.50")).Build()
+	assert.Nil(t, v.Validate(valid))
+	assert.Nil(t, v.Check(valid))
+
+	// market orders have no price, so the notional can't be checked
+	market, _ := MarketOrder("KNOW_BTC").Sell().Size(NewDecimalFromInt(10)).Build()
+	assert.Nil(t, v.Validate(market))
+
+	unknown, _ := LimitOrder("EOS_BTC").Buy().Price(MustParseDecimal("0.1")).Size(NewDecimalFromInt(1)).Build()
+	assert.Equal(t, []ViolationRule{ViolationUnknownMarket}, rules(v.Validate(unknown)))
+
+	disabled, _ := LimitOrder("GTO_BTC").Buy().Price(MustParseDecimal("0.1")).Size(MustParseDecimal("1.5")).Build()
+	assert.Equal(t, []ViolationRule{ViolationTradingDisabled, ViolationSizePrecision}, rules(v.Validate(disabled)))
+
+	imprecise, _ := StopLimitOrder("KNOW_BTC").Buy().StopPrice(MustParseDecimal("0.000012301")).
+		Price(MustParseDecimal("0.000012301")).Size(MustParseDecimal("1000.001")).Build()
+	violations := v.Validate(imprecise)
+	assert.Equal(t, []ViolationRule{ViolationPricePrecision, ViolationPricePrecision, ViolationSizePrecision}, rules(violations))
+	assert.Equal(t, "order_price", violations[0].Field)
+	assert.Equal(t, "stop_price", violations[1].Field)
+	assert.Equal(t, "price 0.000012301 has 9 decimal places, KNOW_BTC allows 8", violations[0].Message)
+
+	small, _ := LimitOrder("KNOW_BTC").Buy().Price(MustParseDecimal("0.00001")).Size(NewDecimalFromInt(5)).Build()
+	violations = v.Validate(small)
+	assert.Equal(t, []ViolationRule{ViolationMinimumSize, ViolationMinimumNotional}, rules(violations))
+	assert.Equal(t, "total 0.00005 BTC is below the minimum of 0.001 BTC", violations[1].Message)
+
+	err := v.Check(small)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+	var violationsErr *OrderViolationsError
+	assert.True(t, errors.As(err, &violationsErr))
+	assert.Len(t, violationsErr.Violations, 2)
+}
+
+func TestWithOrderValidator(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithOrderValidator(NewOrderValidator(testExchangeInformation())))
+	assert.Nil(t, err)
+
+	small, _ := LimitOrder("KNOW_BTC").Buy().Price(MustParseDecimal("0.00001")).Size(NewDecimalFromInt(5)).Build()
+	_, err = client.NewOrder(small)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	valid, _ := LimitOrder("KNOW_BTC").Buy().Price(MustParseDecimal("0.00001230")).Size(NewDecimalFromInt(100)).Build()
+	resp, err := client.NewOrder(valid)
+	assert.Nil(t, err)
+	assert.Equal(t, "02140bef-0c98-4997-9412-9e7ca6f1cc0e", resp.OrderID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, err = NewClient("key", "secret", WithOrderValidator(nil))
+	assert.NotNil(t, err)
+}
+
+func TestWithOrderValidatorSource(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"order_id": "02140bef-0c98-4997-9412-9e7ca6f1cc0e"}`))
+	}))
+	defer ts.Close()
+
+	source := &fakeMetadataSource{info: testExchangeInformation()}
+	cache := NewMetadataCache(source, time.Minute)
+	client, err := NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithOrderValidatorSource(cache))
+	assert.Nil(t, err)
+
+	order, _ := LimitOrder("KNOW_BTC").Buy().Price(MustParseDecimal("0.00001230")).Size(NewDecimalFromInt(100)).Build()
+	_, err = client.NewOrder(order)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// later orders are checked against the changed rules
+	disabled := testExchangeInformation()
+	disabled.Symbols[0].AllowTrading = false
+	source.set(disabled, nil)
+	assert.Nil(t, cache.Refresh(context.Background()))
+	_, err = client.NewOrder(order)
+	var violationsErr *OrderViolationsError
+	assert.True(t, errors.As(err, &violationsErr), err)
+	assert.Equal(t, ViolationTradingDisabled, violationsErr.Violations[0].Rule)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// without rules no order is sent
+	failing := NewMetadataCache(&fakeMetadataSource{err: errors.New("unavailable")}, time.Minute)
+	client, err = NewClient("key", "secret", WithAccountAPIEndpoint(ts.URL), WithOrderValidatorSource(failing))
+	assert.Nil(t, err)
+	_, err = client.NewOrder(order)
+	assert.EqualError(t, err, "error getting order rules, unavailable")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, err = NewClient("key", "secret", WithOrderValidatorSource(nil))
+	assert.NotNil(t, err)
+}